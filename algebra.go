@@ -0,0 +1,304 @@
+package version
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Specifiers don't describe plain intervals of versions: '<V' and '>V' exclude pre, post and local
+// releases of V, and '===' matches a string rather than a version. So the relations between sets
+// are decided on witnesses, which are candidate versions picked from every cell cut by the bounds of
+// the specifiers: the versions of a cell are all satisfied by a specifier or none of them are. Two
+// sets of specifiers are equivalent if they agree on all the witnesses of their specifiers.
+
+// IsEmpty reports whether no version satisfies the set, e.g. '>2,<1' or '>=1.0,<2,!=1.*'.
+func (s *SpecifierSet) IsEmpty() bool {
+	for _, w := range witnesses(s.specs) {
+		if s.Contains(w) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsSubsetOf reports whether every version satisfying s also satisfies other.
+func (s *SpecifierSet) IsSubsetOf(other *SpecifierSet) bool {
+	for _, w := range witnesses(concatSpecifiers(s.specs, other.specs)) {
+		if s.Contains(w) && !other.Contains(w) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Intersect returns the simplified set satisfied by versions satisfying both s and other, use
+// IsEmpty to check if the result is satisfiable.
+func (s *SpecifierSet) Intersect(other *SpecifierSet) *SpecifierSet {
+	return NewSpecifierSet(concatSpecifiers(s.specs, other.specs)...).Simplify()
+}
+
+// Union returns the simplified set satisfied by versions satisfying either s or other. Since a set is
+// a conjunction of specifiers, an error of ErrNotExpressible is returned if the union is not, e.g.
+// '<1' and '>2', or '<2' and '>=2' which excludes pre-releases of 2.
+func (s *SpecifierSet) Union(other *SpecifierSet) (*SpecifierSet, error) {
+	if s.IsSubsetOf(other) {
+		return other.Simplify(), nil
+	}
+	if other.IsSubsetOf(s) {
+		return s.Simplify(), nil
+	}
+
+	// the union can only be made up of specifiers satisfied by both sets
+	specs := concatSpecifiers(s.specs, other.specs)
+	ws := witnesses(specs)
+	var common []*Specifier
+	for _, spec := range specs {
+		if impliedOn(ws, s.specs, spec) && impliedOn(ws, other.specs, spec) {
+			common = append(common, spec)
+		}
+	}
+
+	union := NewSpecifierSet(common...)
+	for _, w := range ws {
+		if union.Contains(w) != (s.Contains(w) || other.Contains(w)) {
+			return nil, fmt.Errorf("union of '%s' and '%s': %w", s, other, ErrNotExpressible)
+		}
+	}
+
+	return union.Simplify(), nil
+}
+
+// Simplify returns an equivalent set without duplicated or redundant specifiers, e.g. '~=1.6' for
+// '>=1.4,<2,!=1.5.*,~=1.6'. The specifiers left keep their order.
+func (s *SpecifierSet) Simplify() *SpecifierSet {
+	ws := witnesses(s.specs)
+
+	var specs []*Specifier
+	seen := make(map[string]bool)
+	for _, spec := range s.specs {
+		if key := spec.String(); !seen[key] {
+			seen[key] = true
+			specs = append(specs, spec)
+		}
+	}
+
+	// the weaker specifiers satisfied by more witnesses are removed first to keep the stronger ones,
+	// and the latter one is removed first among equivalent specifiers
+	satisfied := make(map[*Specifier]int)
+	for _, spec := range specs {
+		for _, w := range ws {
+			if spec.Contains(w) {
+				satisfied[spec]++
+			}
+		}
+	}
+	order := append([]*Specifier(nil), specs...)
+	sort.SliceStable(order, func(i, j int) bool {
+		return satisfied[order[i]] > satisfied[order[j]] || (satisfied[order[i]] == satisfied[order[j]] && i > j)
+	})
+
+	// a specifier is redundant if it's implied by the rest ones
+	removed := make(map[*Specifier]bool)
+	for _, spec := range order {
+		var rest []*Specifier
+		for _, other := range specs {
+			if other != spec && !removed[other] {
+				rest = append(rest, other)
+			}
+		}
+		if impliedOn(ws, rest, spec) {
+			removed[spec] = true
+		}
+	}
+
+	var simplified []*Specifier
+	for _, spec := range specs {
+		if !removed[spec] {
+			simplified = append(simplified, spec)
+		}
+	}
+
+	return &SpecifierSet{specs: simplified}
+}
+
+// impliedOn reports whether spec is satisfied by all the witnesses satisfying specs.
+func impliedOn(ws Versions, specs []*Specifier, spec *Specifier) bool {
+	set := &SpecifierSet{specs: specs}
+	for _, w := range ws {
+		if set.Contains(w) && !spec.Contains(w) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func concatSpecifiers(a, b []*Specifier) []*Specifier {
+	specs := make([]*Specifier, 0, len(a)+len(b))
+	specs = append(specs, a...)
+	return append(specs, b...)
+}
+
+// witnessBuilder collects witnesses around the versions of specifiers.
+type witnessBuilder struct {
+	// huge is larger than any number of the versions, which stands for the unbounded numbers.
+	huge      number
+	witnesses Versions
+	// seen is the added anchors and releases, which are shared by many specifiers.
+	seen map[string]bool
+}
+
+// witnesses returns the witnesses of specs, which are the versions around each bound of specs with
+// all the combinations of pre, post, dev and local segments, in order that every cell has one.
+func witnesses(specs []*Specifier) Versions {
+	// a legacy version never equals an arbitrary equality since it has a whitespace
+	legacy, _ := ParseLegacyVersion("legacy version")
+	b := &witnessBuilder{witnesses: Versions{legacy}, seen: make(map[string]bool)}
+
+	var anchors []*Version
+	for _, spec := range specs {
+		if spec.operator == OpArbitrary {
+			// the only version matching an arbitrary equality is parsed as a pep-440 version if it's
+			// valid and normalized, otherwise it can only be a legacy version
+			if v, err := ParseVersion(spec.version); err == nil {
+				anchors = append(anchors, v)
+				if strings.EqualFold(v.Complete(), spec.version) {
+					b.witnesses = append(b.witnesses, v)
+					continue
+				}
+			}
+			legacy, _ := ParseLegacyVersion(spec.version)
+			b.witnesses = append(b.witnesses, legacy)
+			continue
+		}
+
+		anchors = append(anchors, spec.spec)
+		if spec.lower != nil {
+			anchors = append(anchors, spec.lower, spec.upper)
+		}
+	}
+
+	digits := 1
+	for _, a := range anchors {
+		for _, n := range a.numbers() {
+			if len(n) > digits {
+				digits = len(n)
+			}
+		}
+	}
+	b.huge = number("1" + strings.Repeat("0", digits))
+
+	b.release("0", []number{"0"})
+	for _, a := range anchors {
+		b.around(a)
+	}
+
+	return b.witnesses
+}
+
+// numbers returns all the numbers of v.
+func (v *Version) numbers() []number {
+	numbers := append([]number{v.epoch}, v.release...)
+	for _, s := range []*segment{v.pre, v.post, v.dev} {
+		if s != nil {
+			numbers = append(numbers, s.number)
+		}
+	}
+
+	return numbers
+}
+
+// around adds the witnesses of a's release with the stages around a's ones, and the witnesses of the
+// releases and epochs next to a's.
+func (b *witnessBuilder) around(a *Version) {
+	key := "anchor " + a.Complete()
+	if b.seen[key] {
+		return
+	}
+	b.seen[key] = true
+
+	var pres []*segment
+	for _, name := range []string{"a", "b", "rc"} {
+		if a.pre != nil && a.pre.name == name {
+			pres = append(pres, b.segments(name, a.pre)...)
+		} else {
+			pres = append(pres, b.segments(name, nil)...)
+		}
+	}
+	local := append(append([]string(nil), a.local...), "0")
+	b.product(a.epoch, a.release, pres, b.segments("post", a.post), b.segments("dev", a.dev), [][]string{a.local, local})
+
+	release := a.release
+	n := len(release)
+	b.release(a.epoch, append(release[:n:n], "1"))
+	b.release(a.epoch, append(release[:n-1:n-1], release[n-1].inc()))
+	for i := n - 1; i >= 0; i-- {
+		if !release[i].isZero() {
+			// the highest release below a's one, e.g. 1.4.<huge> for 1.5
+			b.release(a.epoch, append(release[:i:i], release[i].dec(), b.huge))
+			break
+		}
+	}
+
+	b.release(a.epoch.inc(), []number{"0"})
+	if !a.epoch.isZero() {
+		b.release(a.epoch.dec(), []number{b.huge})
+	}
+}
+
+// release adds the witnesses of a release which isn't the one of any bound, so specifiers only tell
+// apart its pre, post, dev and local releases from others but not their numbers.
+func (b *witnessBuilder) release(epoch number, release []number) {
+	key := "release " + (&Version{epoch: epoch, release: release}).Base()
+	if b.seen[key] {
+		return
+	}
+	b.seen[key] = true
+
+	pre := []*segment{{name: "a", number: "0"}}
+	post := []*segment{nil, {name: "post", number: "0"}}
+	dev := []*segment{nil, {name: "dev", number: "0"}}
+	b.product(epoch, release, pre, post, dev, [][]string{nil, {"0"}})
+}
+
+// segments returns nil and the segments named name with the numbers around s, or with the lowest and
+// the highest numbers if s is nil. Note that nil is only included in the result for post and dev.
+func (b *witnessBuilder) segments(name string, s *segment) []*segment {
+	numbers := []number{"0", b.huge}
+	if s != nil {
+		numbers = append(numbers, s.number, s.number.inc())
+	}
+
+	var segments []*segment
+	if name == "post" || name == "dev" {
+		segments = append(segments, nil)
+	}
+	for _, n := range numbers {
+		segments = append(segments, &segment{name: name, number: n})
+	}
+
+	return segments
+}
+
+func (b *witnessBuilder) product(epoch number, release []number, pres, posts, devs []*segment, locals [][]string) {
+	pres = append([]*segment{nil}, pres...)
+	for _, pre := range pres {
+		for _, post := range posts {
+			for _, dev := range devs {
+				for _, local := range locals {
+					b.witnesses = append(b.witnesses, &Version{
+						epoch:   epoch,
+						release: release,
+						pre:     pre,
+						post:    post,
+						dev:     dev,
+						local:   local,
+					})
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package version
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSpecifierSetIsEmpty(t *testing.T) {
+	var emptyCases = []struct {
+		specs    string
+		expected bool
+	}{
+		{"", false},
+		{">=1.4,<2,!=1.5.*,~=1.6", false},
+		{">2,<1", true},
+		{">=1,<1", true},
+		{">=1,<=1", false},
+		{">1,<1.0.post1", true},
+		{">1,<=1.0.post1", true},
+		{">1,<1.0.0.1", false},
+		{">=1.0,!=1.*", false},
+		{">=1.0,<2,!=1.*", true},
+		{"==1.*,<1.0", true},
+		{"==1.*,<=1.0.dev0", false},
+		{"<1.0,>=1.0.dev0", true},
+		{"<1.0rc1,>=1.0.dev0", false},
+		{"==1.0+local,!=1.0", true},
+		{"==1.0,!=1.0+local", false},
+		{"~=1.4.5,!=1.4.*", true},
+		{"===lolwat", false},
+		{"===lolwat,>=0", true},
+		{"===1.0,>=1", false},
+		{"===1.0.0,==1", false},
+		{"===V1.0,==1", true},
+		{"<1!0,>=99999999999999999999", false},
+		{"<1!0,>99999999999999999999.99999999999999999999.post99999999999999999999", false},
+	}
+
+	for _, c := range emptyCases {
+		t.Run(c.specs, func(t *testing.T) {
+			set, err := ParseSpecifierSet(c.specs)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if set.IsEmpty() != c.expected {
+				t.Errorf("%v(actual) != %v(expected)", set.IsEmpty(), c.expected)
+			}
+		})
+	}
+}
+
+func TestSpecifierSetIsSubsetOf(t *testing.T) {
+	var subsetCases = []struct {
+		specs    string
+		other    string
+		expected bool
+	}{
+		{"~=1.6", ">=1.4,<2", true},
+		{">=1.4,<2", "~=1.6", false},
+		{"==1.5.*", "<1.6", true},
+		{"<1.6", "==1.5.*", false},
+		{"==1.0", "==1.0.0", true},
+		{"==1.0", "===1.0", false},
+		{"===1.0", "==1.0", true},
+		{"===1.0", ">=1.0,<2", true},
+		{"===1.0.post1", "==1.0", false},
+		{"===1.0-1", "==1.0.post1", false},
+		{"===lolwat", "==1.0", false},
+		{">1.0", ">=1.0.post1", true},
+		{">1.0", ">1.0.post99", true},
+		{">1.0", ">=1.0.0.1.dev0", false},
+		{"<2", "<2.0.dev0", true},
+		{"<2.0rc1", "<2.0.dev0", false},
+		{"", ">=0.dev0", false},
+		{">2,<1", "==1.0", true},
+		{"!=1.0", "", true},
+	}
+
+	for _, c := range subsetCases {
+		t.Run(c.specs+"/"+c.other, func(t *testing.T) {
+			set, err := ParseSpecifierSet(c.specs)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			other, err := ParseSpecifierSet(c.other)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if set.IsSubsetOf(other) != c.expected {
+				t.Errorf("%v(actual) != %v(expected)", set.IsSubsetOf(other), c.expected)
+			}
+		})
+	}
+}
+
+func TestSpecifierSetSimplify(t *testing.T) {
+	var simplifyCases = []struct {
+		specs    string
+		expected string
+	}{
+		{">=1.4,<2,!=1.5.*,~=1.6", "~=1.6"},
+		{">=1.4, >=1.4,<2", "<2,>=1.4"},
+		{">1,>=1", ">1"},
+		{"<=2,<2", "<2"},
+		{"<2.0,<2.0.dev0", "<2.0"},
+		{"!=1.5,!=1.5.*", "!=1.5.*"},
+		{"==1.0,>=0.9,!=2.0", "==1.0"},
+		{"~=1.4.5,>=1.4.5,<1.5", "~=1.4.5"},
+		{">2,<1,!=1.5", "<1,>2"},
+		{"===lolwat,!=1.0", "!=1.0,===lolwat"},
+	}
+
+	for _, c := range simplifyCases {
+		t.Run(c.specs, func(t *testing.T) {
+			set, err := ParseSpecifierSet(c.specs)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if actual := set.Simplify().String(); actual != c.expected {
+				t.Errorf("%s(actual) != %s(expected)", actual, c.expected)
+			}
+		})
+	}
+}
+
+func TestSpecifierSetIntersect(t *testing.T) {
+	set, _ := ParseSpecifierSet(">=1.4,<2")
+	other, _ := ParseSpecifierSet("!=1.5.*,~=1.6")
+
+	intersection := set.Intersect(other)
+	if intersection.String() != "~=1.6" || intersection.IsEmpty() {
+		t.Errorf("%s(actual) != ~=1.6(expected)", intersection)
+	}
+
+	other, _ = ParseSpecifierSet(">=2")
+	if intersection = set.Intersect(other); !intersection.IsEmpty() {
+		t.Errorf("%s should be empty", intersection)
+	}
+}
+
+func TestSpecifierSetUnion(t *testing.T) {
+	var unionCases = []struct {
+		specs    string
+		other    string
+		expected string
+	}{
+		{">=1,<2", ">=1.5,<3", "<3,>=1"},
+		{"~=1.6", ">=1.4,<2", "<2,>=1.4"},
+		{"==1.0", "==1.0.0", "==1.0.0"},
+		{">2,<1", "==1.0", "==1.0"},
+		{">=1,!=1.5", ">=1,!=1.6", ">=1"},
+		{"<1", ">2", ""},
+		{">=1,<2", ">=2,<3", ""},
+		{"==1.0", "==2.0", ""},
+	}
+
+	for _, c := range unionCases {
+		t.Run(c.specs+"/"+c.other, func(t *testing.T) {
+			set, err := ParseSpecifierSet(c.specs)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			other, err := ParseSpecifierSet(c.other)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			union, err := set.Union(other)
+			if c.expected == "" {
+				if !errors.Is(err, ErrNotExpressible) {
+					t.Errorf("%v should be a not expressible error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if union.String() != c.expected {
+				t.Errorf("%s(actual) != %s(expected)", union, c.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package version
+
+import (
+	"fmt"
+)
+
+// clone returns a shallow copy of version, the segments are shared since they are never modified.
+func (v *Version) clone() *Version {
+	c := *v
+	return &c
+}
+
+// BumpRelease increases the release segment at index by one and resets the following segments to
+// zeros, missing segments are padded with zeros, e.g. bumping 1.2 at index 2 results in 1.2.1.
+// Pre, post, dev and local segments are dropped, so 2.0rc1 is bumped to 3.0 at index 0.
+func (v *Version) BumpRelease(index int) (*Version, error) {
+	if index < 0 {
+		return nil, &VersionError{Version: v.Complete(), Component: ComponentRelease, Kind: ErrInvalidVersion, Err: fmt.Errorf("negative index %d", index)}
+	}
+
+	n := len(v.release)
+	if index >= n {
+		n = index + 1
+	}
+	release := make([]number, n)
+	copy(release, v.release)
+	for i := len(v.release); i < n; i++ {
+		release[i] = "0"
+	}
+	release[index] = release[index].inc()
+	for i := index + 1; i < n; i++ {
+		release[i] = "0"
+	}
+
+	return &Version{
+		epoch:   v.epoch,
+		release: release,
+	}, nil
+}
+
+// NextPre returns the next pre-release named name ('a', 'b', 'rc' or their alternative spellings):
+// the number is increased if v is already a pre-release of the same name, otherwise it starts from
+// zero, e.g. 1.0rc1 results in 1.0rc2 and 1.0b2 in 1.0rc0. A dev release of the pre-release results
+// in the pre-release it leads to, e.g. 1.0b1.dev0 in 1.0b1. It's an error if name is before the
+// current pre-release, e.g. 'a' for 1.0rc1. Post, dev and local segments are dropped. Note that the
+// next pre-release of a final release such as 1.0 is 1.0rc0, which sorts before 1.0, bump the
+// release first to start pre-releases of a new version.
+func (v *Version) NextPre(name string) (*Version, error) {
+	pre, err := newStage(&Stage{Name: name}, "a", "b", "rc")
+	if err != nil {
+		return nil, &VersionError{Version: v.Complete(), Component: ComponentPre, Kind: ErrInvalidVersion, Err: err}
+	}
+	if v.pre != nil {
+		// the names of pre-releases happen to sort the same as the stages
+		switch {
+		case pre.name < v.pre.name:
+			return nil, &VersionError{Version: v.Complete(), Component: ComponentPre, Kind: ErrInvalidVersion,
+				Err: fmt.Errorf("'%s' is before the current pre-release '%s%s'", pre.name, v.pre.name, v.pre.number)}
+		case pre.name == v.pre.name && v.dev != nil && v.post == nil:
+			pre.number = v.pre.number
+		case pre.name == v.pre.name:
+			pre.number = v.pre.number.inc()
+		}
+	}
+
+	return &Version{
+		epoch:   v.epoch,
+		release: v.release,
+		pre:     pre,
+	}, nil
+}
+
+// NextPost returns the next post release, the number is increased if v is already a post release,
+// otherwise it starts from zero, and a dev release of a post release such as 1.0.post1.dev0 results
+// in the post release 1.0.post1. Dev and local segments are dropped.
+func (v *Version) NextPost() *Version {
+	c := v.clone()
+	c.post = &segment{name: "post", number: "0"}
+	switch {
+	case v.post != nil && v.dev != nil:
+		c.post.number = v.post.number
+	case v.post != nil:
+		c.post.number = v.post.number.inc()
+	}
+	c.dev = nil
+	c.local = nil
+
+	return c
+}
+
+// NextDev returns the next dev release, the number is increased if v is already a dev release,
+// otherwise it starts from zero. Local segment is dropped.
+func (v *Version) NextDev() *Version {
+	c := v.clone()
+	c.dev = &segment{name: "dev", number: "0"}
+	if v.dev != nil {
+		c.dev.number = v.dev.number.inc()
+	}
+	c.local = nil
+
+	return c
+}
+
+// DropLocal returns the public version of v.
+func (v *Version) DropLocal() *Version {
+	c := v.clone()
+	c.local = nil
+
+	return c
+}
+
+// WithLocal returns v with its local segment replaced, segments of local can be separated by '.',
+// '-' or '_', and an empty local drops the local segment.
+func (v *Version) WithLocal(local string) (*Version, error) {
+	if local == "" {
+		return v.DropLocal(), nil
+	}
+	if !localVersionRe.MatchString(local) {
+		return nil, &VersionError{Version: v.Complete(), Component: ComponentLocal, Kind: ErrInvalidVersion, Err: fmt.Errorf("unexpected local '%s'", local)}
+	}
+
+	c := v.clone()
+	c.local = parseLocalVersion(local)
+
+	return c, nil
+}
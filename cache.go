@@ -0,0 +1,158 @@
+package version
+
+import (
+	"container/list"
+	"sync"
+)
+
+// VersionCache is a concurrency-safe LRU cache of parsed versions and canonicalized package names,
+// for bulk processing which sees the same strings repeatedly. Every caller gets its own copy of a
+// cached version, so decoding into it, e.g. with UnmarshalText, doesn't affect the cache. A nil
+// *VersionCache is valid and caches nothing.
+type VersionCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  *list.List
+	index    map[cacheKey]*list.Element
+	stats    CacheStats
+}
+
+// CacheStats is the statistics of a VersionCache.
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	// Len is the number of entries in the cache.
+	Len int
+}
+
+type cacheKind int
+
+const (
+	cacheParse cacheKind = iota
+	cacheParseVersion
+	cacheCanonicalizePackage
+)
+
+type cacheKey struct {
+	kind  cacheKind
+	input string
+}
+
+type cacheEntry struct {
+	key   cacheKey
+	value interface{}
+	err   error
+}
+
+// NewVersionCache creates a cache holding at most capacity entries, the least recently used entry is
+// evicted if it's full. A non-positive capacity means the cache is unbounded.
+func NewVersionCache(capacity int) *VersionCache {
+	return &VersionCache{
+		capacity: capacity,
+		entries:  list.New(),
+		index:    make(map[cacheKey]*list.Element),
+	}
+}
+
+// Parse is the cached Parse.
+func (c *VersionCache) Parse(version string) (IVersion, error) {
+	value, err := c.get(cacheKey{cacheParse, version}, func() (interface{}, error) {
+		return Parse(version)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return copyVersion(value.(IVersion)), nil
+}
+
+// ParseVersion is the cached ParseVersion, errors of invalid versions are cached as well.
+func (c *VersionCache) ParseVersion(version string) (*Version, error) {
+	value, err := c.get(cacheKey{cacheParseVersion, version}, func() (interface{}, error) {
+		return ParseVersion(version)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return copyVersion(value.(*Version)).(*Version), nil
+}
+
+// CanonicalizePackage is the cached CanonicalizePackage.
+func (c *VersionCache) CanonicalizePackage(name string) string {
+	value, _ := c.get(cacheKey{cacheCanonicalizePackage, name}, func() (interface{}, error) {
+		return CanonicalizePackage(name), nil
+	})
+
+	return value.(string)
+}
+
+// copyVersion returns a shallow copy of v, the slices are shared since a version is only modified as
+// a whole by the decoding methods, which never write into the slices.
+func copyVersion(v IVersion) IVersion {
+	switch v := v.(type) {
+	case *Version:
+		copied := *v
+		return &copied
+	case *LegacyVersion:
+		copied := *v
+		return &copied
+	default:
+		return v
+	}
+}
+
+// Stats returns the statistics of cache.
+func (c *VersionCache) Stats() CacheStats {
+	if c == nil {
+		return CacheStats{}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Len = c.entries.Len()
+	return stats
+}
+
+// get returns the cached value of key, or computes and caches it by load on a miss. The lock isn't
+// held while loading, so the same key may be loaded concurrently, which is harmless since the
+// results are the same.
+func (c *VersionCache) get(key cacheKey, load func() (interface{}, error)) (interface{}, error) {
+	if c == nil {
+		return load()
+	}
+
+	c.mu.Lock()
+	if elem, ok := c.index[key]; ok {
+		c.entries.MoveToFront(elem)
+		c.stats.Hits++
+		entry := elem.Value.(*cacheEntry)
+		c.mu.Unlock()
+		return entry.value, entry.err
+	}
+	c.stats.Misses++
+	c.mu.Unlock()
+
+	value, err := load()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.index[key]; ok {
+		// loaded by another goroutine meanwhile, share its value
+		c.entries.MoveToFront(elem)
+		entry := elem.Value.(*cacheEntry)
+		return entry.value, entry.err
+	}
+	c.index[key] = c.entries.PushFront(&cacheEntry{key: key, value: value, err: err})
+	if c.capacity > 0 && c.entries.Len() > c.capacity {
+		oldest := c.entries.Back()
+		c.entries.Remove(oldest)
+		delete(c.index, oldest.Value.(*cacheEntry).key)
+		c.stats.Evictions++
+	}
+
+	return value, err
+}
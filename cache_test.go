@@ -0,0 +1,140 @@
+package version
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestVersionCache(t *testing.T) {
+	cache := NewVersionCache(2)
+
+	v1, err := cache.ParseVersion("1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v2, _ := cache.ParseVersion("1.0")
+	if v1 == v2 || !v1.Equal(v2) {
+		t.Errorf("cached version should be copied for every caller")
+	}
+
+	if _, err = cache.ParseVersion("lolwat"); !errors.Is(err, ErrInvalidVersion) {
+		t.Errorf("%v should be an invalid version error", err)
+	}
+	if _, err = cache.ParseVersion("lolwat"); !errors.Is(err, ErrInvalidVersion) {
+		t.Errorf("cached %v should be an invalid version error", err)
+	}
+
+	// 1.0 is evicted as the least recently used entry
+	if v, err := cache.Parse("lolwat"); err != nil || v.Complete() != "lolwat" {
+		t.Errorf("%v(actual) != lolwat(expected), %v", v, err)
+	}
+	if v, _ := cache.ParseVersion("1.0"); !v.Equal(v1) {
+		t.Errorf("%s(actual) != %s(expected)", v, v1)
+	}
+
+	expected := CacheStats{Hits: 2, Misses: 4, Evictions: 2, Len: 2}
+	if stats := cache.Stats(); stats != expected {
+		t.Errorf("%+v(actual) != %+v(expected)", stats, expected)
+	}
+}
+
+func TestVersionCacheDecode(t *testing.T) {
+	cache := NewVersionCache(0)
+
+	v, _ := cache.ParseVersion("1.0")
+	if err := v.UnmarshalText([]byte("2.0")); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(`"3.0"`), v); err != nil {
+		t.Fatal(err)
+	}
+	if cached, _ := cache.ParseVersion("1.0"); cached.Complete() != "1.0" {
+		t.Errorf("%s(actual) != 1.0(expected)", cached.Complete())
+	}
+
+	legacy, _ := cache.Parse("lolwat")
+	if err := legacy.(*LegacyVersion).Scan("foo"); err != nil {
+		t.Fatal(err)
+	}
+	if cached, _ := cache.Parse("lolwat"); cached.Complete() != "lolwat" {
+		t.Errorf("%s(actual) != lolwat(expected)", cached.Complete())
+	}
+}
+
+func TestVersionCacheNamespaces(t *testing.T) {
+	cache := NewVersionCache(0)
+
+	if name := cache.CanonicalizePackage("Foo.Bar"); name != "foo-bar" {
+		t.Errorf("%s(actual) != foo-bar(expected)", name)
+	}
+	if v, err := cache.Parse("1.0"); err != nil || v.Complete() != "1.0" {
+		t.Errorf("%v(actual) != 1.0(expected), %v", v, err)
+	}
+	if v, err := cache.ParseVersion("1.0"); err != nil || v.String() != "Version<1.0>" {
+		t.Errorf("%v(actual) != Version<1.0>(expected), %v", v, err)
+	}
+
+	if stats := cache.Stats(); stats.Hits != 0 || stats.Len != 3 {
+		t.Errorf("%+v should have no hits and 3 entries", stats)
+	}
+}
+
+func TestVersionCacheNil(t *testing.T) {
+	var cache *VersionCache
+
+	if v, err := cache.ParseVersion("1.0"); err != nil || v.Complete() != "1.0" {
+		t.Errorf("%v(actual) != 1.0(expected), %v", v, err)
+	}
+	if name := cache.CanonicalizePackage("Foo_Bar"); name != "foo-bar" {
+		t.Errorf("%s(actual) != foo-bar(expected)", name)
+	}
+	if stats := cache.Stats(); stats != (CacheStats{}) {
+		t.Errorf("%+v should be empty", stats)
+	}
+}
+
+func TestVersionCacheConcurrency(t *testing.T) {
+	cache := NewVersionCache(16)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				version := fmt.Sprintf("1.%d", (i+j)%32)
+				v, err := cache.ParseVersion(version)
+				if err != nil || v.Complete() != version {
+					t.Errorf("%v(actual) != %s(expected), %v", v, version, err)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if stats := cache.Stats(); stats.Hits+stats.Misses != 8000 || stats.Len != 16 {
+		t.Errorf("%+v should have 8000 lookups and 16 entries", stats)
+	}
+}
+
+func TestPackageWithCache(t *testing.T) {
+	cache := NewVersionCache(128)
+	p, _ := NewPackage("foo")
+	cached := p.WithCache(cache)
+
+	for _, filename := range []string{"foo-1.0.tar.gz", "foo-1.0-py3-none-any.whl", "foo-1.0.tar.gz", "foo-1.0.exe"} {
+		expected, expectedErr := p.EvaluateVersion(filename)
+		actual, err := cached.EvaluateVersion(filename)
+		if actual != expected || (err == nil) != (expectedErr == nil) {
+			t.Errorf("%s(actual) != %s(expected), %v", actual, expected, err)
+		}
+	}
+
+	if stats := cache.Stats(); stats.Hits == 0 {
+		t.Errorf("%+v should have hits", stats)
+	}
+}
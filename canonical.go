@@ -0,0 +1,249 @@
+package version
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// NormalizationRule is one of the rules ParseVersion applies to turn a version into its normalized
+// form, see https://peps.python.org/pep-0440/#normalization.
+type NormalizationRule int
+
+const (
+	// RuleWhitespace strips leading and trailing whitespaces, e.g. ' 1.0 ' -> '1.0'.
+	RuleWhitespace NormalizationRule = iota + 1
+	// RuleLeadingV drops the preceding 'v', e.g. 'v1.0' -> '1.0'.
+	RuleLeadingV
+	// RuleCase lowercases letters, e.g. '1.0RC1' -> '1.0rc1'.
+	RuleCase
+	// RuleSpelling replaces alternative spellings of stages, e.g. '1.0alpha1' -> '1.0a1'.
+	RuleSpelling
+	// RuleSeparator fixes separators around stages, e.g. '1.0-a.1' -> '1.0a1', '1.0post1' -> '1.0.post1'.
+	RuleSeparator
+	// RuleImplicitNumber adds the implicit number of stages, e.g. '1.0a' -> '1.0a0'.
+	RuleImplicitNumber
+	// RuleImplicitPost rewrites the implicit post release, e.g. '1.0-1' -> '1.0.post1'.
+	RuleImplicitPost
+	// RuleLeadingZeros strips leading zeros of integers, e.g. '1.01' -> '1.1'.
+	RuleLeadingZeros
+	// RuleZeroEpoch drops the explicit zero epoch, e.g. '0!1.0' -> '1.0'.
+	RuleZeroEpoch
+	// RuleLocalSeparator replaces separators of local segment with '.', e.g. '1.0+a-b' -> '1.0+a.b'.
+	RuleLocalSeparator
+)
+
+func (r NormalizationRule) String() string {
+	switch r {
+	case RuleWhitespace:
+		return "whitespace"
+	case RuleLeadingV:
+		return "leading-v"
+	case RuleCase:
+		return "case"
+	case RuleSpelling:
+		return "spelling"
+	case RuleSeparator:
+		return "separator"
+	case RuleImplicitNumber:
+		return "implicit-number"
+	case RuleImplicitPost:
+		return "implicit-post"
+	case RuleLeadingZeros:
+		return "leading-zeros"
+	case RuleZeroEpoch:
+		return "zero-epoch"
+	case RuleLocalSeparator:
+		return "local-separator"
+	default:
+		return fmt.Sprintf("NormalizationRule(%d)", int(r))
+	}
+}
+
+// CanonicalError is returned by ParseCanonical if a version is valid but not in canonical form.
+type CanonicalError struct {
+	Version    string
+	Normalized string
+	Rules      []NormalizationRule
+}
+
+func (e *CanonicalError) Error() string {
+	var rules []string
+	for _, r := range e.Rules {
+		rules = append(rules, r.String())
+	}
+
+	return fmt.Sprintf("version '%s': %s, normalized as '%s' by rules: %s", e.Version, ErrNotCanonical, e.Normalized, strings.Join(rules, ", "))
+}
+
+func (e *CanonicalError) Is(target error) bool {
+	return target == ErrNotCanonical
+}
+
+// IsCanonical reports whether a version is in the canonical form defined by pep-440, which means
+// it's exactly the same as its normalized form.
+func IsCanonical(version string) bool {
+	public, local := version, ""
+	if i := strings.IndexByte(version, '+'); i >= 0 {
+		public, local = version[:i], version[i+1:]
+		if !canonicalLocalRe.MatchString(local) {
+			return false
+		}
+	}
+
+	return versionRe.MatchString(public)
+}
+
+// ParseCanonical parses a version only if it's canonical, for a valid but non-canonical version
+// a *CanonicalError is returned with the normalization rules which would have been applied.
+func ParseCanonical(version string) (*Version, error) {
+	v, err := ParseVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	if !IsCanonical(version) {
+		rules, _ := NormalizationRules(version)
+		return nil, &CanonicalError{
+			Version:    version,
+			Normalized: v.Complete(),
+			Rules:      rules,
+		}
+	}
+
+	return v, nil
+}
+
+// NormalizationRules returns the rules which ParseVersion applies to normalize a version in the
+// order of their definitions, an empty result means the version is canonical.
+func NormalizationRules(version string) ([]NormalizationRule, error) {
+	match := irregularVersionRe.FindStringSubmatch(version)
+	if match == nil {
+		return nil, &VersionError{Version: version, Kind: ErrInvalidVersion}
+	}
+	group := func(name string) string {
+		return match[irregularVersionRe.SubexpIndex(name)]
+	}
+
+	rules := make(map[NormalizationRule]bool)
+	checkNumber := func(number string) {
+		if number == "" {
+			rules[RuleImplicitNumber] = true
+		} else if len(number) > 1 && number[0] == '0' {
+			rules[RuleLeadingZeros] = true
+		}
+	}
+
+	trimmed := strings.TrimSpace(version)
+	if trimmed != version {
+		rules[RuleWhitespace] = true
+	}
+	if strings.HasPrefix(trimmed, "v") || strings.HasPrefix(trimmed, "V") {
+		rules[RuleLeadingV] = true
+		trimmed = trimmed[1:]
+	}
+	if strings.ToLower(trimmed) != trimmed {
+		rules[RuleCase] = true
+	}
+
+	if epoch := group("epoch"); epoch != "" {
+		if strings.Trim(epoch, "0") == "" {
+			rules[RuleZeroEpoch] = true
+		} else if epoch[0] == '0' {
+			rules[RuleLeadingZeros] = true
+		}
+	}
+	for _, r := range strings.Split(group("release"), ".") {
+		if len(r) > 1 && r[0] == '0' {
+			rules[RuleLeadingZeros] = true
+		}
+	}
+
+	if pre := group("pre"); pre != "" {
+		letter, number := group("pre_l"), group("pre_n")
+		if l := strings.ToLower(letter); l != "a" && l != "b" && l != "rc" {
+			rules[RuleSpelling] = true
+		}
+		if pre != letter+number {
+			rules[RuleSeparator] = true
+		}
+		checkNumber(number)
+	}
+
+	if post := group("post"); post != "" {
+		if n := group("post_n1"); n != "" {
+			rules[RuleImplicitPost] = true
+			checkNumber(n)
+		} else {
+			letter, number := group("post_l"), group("post_n2")
+			if strings.ToLower(letter) != "post" {
+				rules[RuleSpelling] = true
+			}
+			if post != "."+letter+number {
+				rules[RuleSeparator] = true
+			}
+			checkNumber(number)
+		}
+	}
+
+	if dev := group("dev"); dev != "" {
+		letter, number := group("dev_l"), group("dev_n")
+		if dev != "."+letter+number {
+			rules[RuleSeparator] = true
+		}
+		checkNumber(number)
+	}
+
+	if strings.ContainsAny(group("local"), "-_") {
+		rules[RuleLocalSeparator] = true
+	}
+
+	var result []NormalizationRule
+	for r := range rules {
+		result = append(result, r)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i] < result[j]
+	})
+
+	return result, nil
+}
+
+// Canonical returns the canonical form of a version with trailing zeros of release stripped, which
+// is the same for versions equal to each other, e.g. '1' for both 1.0 and 1.0.0. A LegacyVersion is
+// returned as it is.
+func Canonical(v IVersion) string {
+	version, ok := v.(*Version)
+	if !ok {
+		return v.Complete()
+	}
+
+	c := version.clone()
+	n := len(c.release)
+	for n > 1 && c.release[n-1].isZero() {
+		n--
+	}
+	c.release = c.release[:n]
+
+	return c.Complete()
+}
+
+// CanonicalizeVersion normalizes a version string as canonicalize_version of packaging does, see
+// https://github.com/pypa/packaging/blob/23.1/src/packaging/utils.py#L55, trailing zeros of release
+// are stripped if stripTrailingZero is true, and an invalid version is returned unchanged.
+func CanonicalizeVersion(version string, stripTrailingZero bool) string {
+	v, err := ParseVersion(version)
+	if err != nil {
+		return version
+	}
+	if stripTrailingZero {
+		return Canonical(v)
+	}
+
+	return v.Complete()
+}
+
+// EqualCanonical reports whether two version strings have the same canonical form, e.g. '1.0' and
+// 'v1.0.0', invalid versions are compared as they are.
+func EqualCanonical(a, b string) bool {
+	return CanonicalizeVersion(a, true) == CanonicalizeVersion(b, true)
+}
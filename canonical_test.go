@@ -0,0 +1,140 @@
+package version
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestIsCanonical(t *testing.T) {
+	var canonicalCases = []struct {
+		version   string
+		canonical bool
+	}{
+		{"1.0", true},
+		{"1.0.0", true},
+		{"1!1.0a1.post2.dev3+ubuntu.1", true},
+		{"0.0.1rc0", true},
+		{"1.0+abc.7", true},
+		{"v1.0", false},
+		{" 1.0", false},
+		{"1.0RC1", false},
+		{"1.0-alpha_1", false},
+		{"1.01", false},
+		{"0!1.0", false},
+		{"1.0+abc-7", false},
+		{"1.0+ABC", false},
+		{"1.0-1", false},
+		{"french toast", false},
+	}
+
+	for _, c := range canonicalCases {
+		t.Run(c.version, func(t *testing.T) {
+			if IsCanonical(c.version) != c.canonical {
+				t.Errorf("%s is canonical, %t(expected)", c.version, c.canonical)
+			}
+		})
+	}
+}
+
+func TestParseCanonical(t *testing.T) {
+	var canonicalCases = []struct {
+		version    string
+		normalized string
+		rules      []NormalizationRule
+	}{
+		{"1.0a1.post2.dev3", "1.0a1.post2.dev3", nil},
+		{"  1.0\t", "1.0", []NormalizationRule{RuleWhitespace}},
+		{"v1.0", "1.0", []NormalizationRule{RuleLeadingV}},
+		{"V1.0", "1.0", []NormalizationRule{RuleLeadingV}},
+		{"v1.0-ALPHA_1", "1.0a1", []NormalizationRule{RuleLeadingV, RuleCase, RuleSpelling, RuleSeparator}},
+		{"1.0RC1", "1.0rc1", []NormalizationRule{RuleCase}},
+		{"1.0c1", "1.0rc1", []NormalizationRule{RuleSpelling}},
+		{"1.0.a1", "1.0a1", []NormalizationRule{RuleSeparator}},
+		{"1.0b", "1.0b0", []NormalizationRule{RuleImplicitNumber}},
+		{"1.0-1", "1.0.post1", []NormalizationRule{RuleImplicitPost}},
+		{"1.0post1", "1.0.post1", []NormalizationRule{RuleSeparator}},
+		{"1.0.rev", "1.0.post0", []NormalizationRule{RuleSpelling, RuleImplicitNumber}},
+		{"1.0-dev-1", "1.0.dev1", []NormalizationRule{RuleSeparator}},
+		{"1.0.dev", "1.0.dev0", []NormalizationRule{RuleImplicitNumber}},
+		{"01.0a01", "1.0a1", []NormalizationRule{RuleLeadingZeros}},
+		{"01!1.0", "1!1.0", []NormalizationRule{RuleLeadingZeros}},
+		{"0!1.0", "1.0", []NormalizationRule{RuleZeroEpoch}},
+		{"1.0+Ubuntu-1_2", "1.0+ubuntu.1.2", []NormalizationRule{RuleCase, RuleLocalSeparator}},
+	}
+
+	for _, c := range canonicalCases {
+		t.Run(c.version, func(t *testing.T) {
+			rules, err := NormalizationRules(c.version)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if !reflect.DeepEqual(rules, c.rules) {
+				t.Errorf("%v(actual) != %v(expected)", rules, c.rules)
+			}
+
+			v, err := ParseCanonical(c.version)
+			if c.rules == nil {
+				if err != nil {
+					t.Error(err)
+				} else if v.Complete() != c.normalized {
+					t.Errorf("%s(actual) != %s(expected)", v.Complete(), c.normalized)
+				}
+				return
+			}
+
+			var canonicalErr *CanonicalError
+			if !errors.As(err, &canonicalErr) {
+				t.Errorf("%v should be a canonical error", err)
+				return
+			}
+			if canonicalErr.Normalized != c.normalized {
+				t.Errorf("%s(actual) != %s(expected)", canonicalErr.Normalized, c.normalized)
+			}
+		})
+	}
+
+	if _, err := ParseCanonical("french toast"); err == nil {
+		t.Error("'french toast' should be an invalid version")
+	}
+}
+
+func TestCanonicalizeVersion(t *testing.T) {
+	var canonicalizeCases = []struct {
+		version  string
+		stripped string
+		complete string
+	}{
+		{"1.0", "1", "1.0"},
+		{"1.0.0", "1", "1.0.0"},
+		{"0.0", "0", "0.0"},
+		{"1.1.0", "1.1", "1.1.0"},
+		{"1.01.0", "1.1", "1.1.0"},
+		{"v1.0.0rc1", "1rc1", "1.0.0rc1"},
+		{"1!1.0.post0.dev0+Ubuntu-1", "1!1.post0.dev0+ubuntu.1", "1!1.0.post0.dev0+ubuntu.1"},
+		{"1.0.1", "1.0.1", "1.0.1"},
+		{"french toast", "french toast", "french toast"},
+	}
+
+	for _, c := range canonicalizeCases {
+		t.Run(c.version, func(t *testing.T) {
+			if actual := CanonicalizeVersion(c.version, true); actual != c.stripped {
+				t.Errorf("%s(actual) != %s(expected)", actual, c.stripped)
+			}
+			if actual := CanonicalizeVersion(c.version, false); actual != c.complete {
+				t.Errorf("%s(actual) != %s(expected)", actual, c.complete)
+			}
+			if v, _ := Parse(c.version); Canonical(v) != c.stripped {
+				t.Errorf("%s(actual) != %s(expected)", Canonical(v), c.stripped)
+			}
+		})
+	}
+
+	if !EqualCanonical("1.0", "v1.0.0") || !EqualCanonical("1.0+local", "1.0.0+LOCAL") {
+		t.Error("1.0 should be equal to 1.0.0 in canonical form")
+	}
+	if EqualCanonical("1.0", "1.0+local") || EqualCanonical("1.0", "1.0.1") {
+		t.Error("1.0 should not be equal to 1.0+local or 1.0.1 in canonical form")
+	}
+}
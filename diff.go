@@ -0,0 +1,165 @@
+package version
+
+import (
+	"fmt"
+)
+
+// Change is the highest-order component changed between two versions, the constants are ordered
+// from the lowest to the highest.
+type Change int
+
+const (
+	// ChangeNone means the versions are equal, e.g. 1.0 and 1.0.0.
+	ChangeNone Change = iota
+	// ChangeLocal means only the local versions differ, e.g. 1.0+cpu and 1.0+cu121.
+	ChangeLocal
+	// ChangeDev means the dev releases differ, e.g. 1.0.dev1 and 1.0.dev2.
+	ChangeDev
+	// ChangePost means the post releases differ, e.g. 1.0 and 1.0.post1.
+	ChangePost
+	// ChangePre means the pre-releases differ, e.g. 1.0rc1 and 1.0.
+	ChangePre
+	// ChangePatch means the third or a later release segment differs, e.g. 1.2.3 and 1.2.4.
+	ChangePatch
+	// ChangeMinor means the second release segment differs, e.g. 1.2 and 1.3.
+	ChangeMinor
+	// ChangeMajor means the first release segment differs, e.g. 1.2 and 2.0.
+	ChangeMajor
+	// ChangeEpoch means the epochs differ, e.g. 2023.1 and 1!1.0.
+	ChangeEpoch
+	// ChangeUnknown means either version is a LegacyVersion, which has no components.
+	ChangeUnknown
+)
+
+func (c Change) String() string {
+	switch c {
+	case ChangeNone:
+		return "none"
+	case ChangeLocal:
+		return "local"
+	case ChangeDev:
+		return "dev"
+	case ChangePost:
+		return "post"
+	case ChangePre:
+		return "pre"
+	case ChangePatch:
+		return "patch"
+	case ChangeMinor:
+		return "minor"
+	case ChangeMajor:
+		return "major"
+	case ChangeEpoch:
+		return "epoch"
+	case ChangeUnknown:
+		return "unknown"
+	default:
+		return fmt.Sprintf("Change(%d)", int(c))
+	}
+}
+
+// Direction tells whether a change is an upgrade or a downgrade.
+type Direction int
+
+const (
+	Unchanged Direction = iota
+	Upgrade
+	Downgrade
+)
+
+func (d Direction) String() string {
+	switch d {
+	case Unchanged:
+		return "unchanged"
+	case Upgrade:
+		return "upgrade"
+	case Downgrade:
+		return "downgrade"
+	default:
+		return fmt.Sprintf("Direction(%d)", int(d))
+	}
+}
+
+// VersionDiff describes the change from a version to another.
+type VersionDiff struct {
+	Change    Change
+	Direction Direction
+	// CrossesPrerelease is true if one version is a pre-release (including dev releases) but the
+	// other one is not, e.g. from 2.0rc1 to 2.0, or from 1.9 to 2.0.dev1.
+	CrossesPrerelease bool
+}
+
+func (d VersionDiff) String() string {
+	return fmt.Sprintf("%s %s", d.Change, d.Direction)
+}
+
+// Diff classifies the change from a to b, the direction follows the order of versions, so that
+// 1.0.post1 to 1.0.post1.dev1 is a downgrade of dev release.
+func Diff(a, b IVersion) VersionDiff {
+	d := VersionDiff{
+		Change:            ChangeUnknown,
+		CrossesPrerelease: isPrerelease(a) != isPrerelease(b),
+	}
+	switch c := a.Compare(b); {
+	case c < 0:
+		d.Direction = Upgrade
+	case c > 0:
+		d.Direction = Downgrade
+	}
+
+	va, okA := a.(*Version)
+	vb, okB := b.(*Version)
+	if okA && okB {
+		d.Change = diffChange(va, vb)
+	}
+
+	return d
+}
+
+func diffChange(a, b *Version) Change {
+	if a.epoch.compare(b.epoch) != 0 {
+		return ChangeEpoch
+	}
+
+	for i := 0; i < len(a.release) || i < len(b.release); i++ {
+		var x, y number
+		if i < len(a.release) {
+			x = a.release[i]
+		}
+		if i < len(b.release) {
+			y = b.release[i]
+		}
+		if x.compare(y) == 0 {
+			continue
+		}
+
+		switch i {
+		case 0:
+			return ChangeMajor
+		case 1:
+			return ChangeMinor
+		default:
+			return ChangePatch
+		}
+	}
+
+	switch {
+	case !equalStage(a.pre, b.pre):
+		return ChangePre
+	case !equalStage(a.post, b.post):
+		return ChangePost
+	case !equalStage(a.dev, b.dev):
+		return ChangeDev
+	case compareLocal(a.local, b.local) != 0:
+		return ChangeLocal
+	default:
+		return ChangeNone
+	}
+}
+
+func equalStage(a, b *segment) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.name == b.name && a.number.compare(b.number) == 0
+}
@@ -0,0 +1,43 @@
+package version
+
+import (
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	var diffCases = []struct {
+		a, b     string
+		expected VersionDiff
+	}{
+		{"1.0", "1.0.0", VersionDiff{ChangeNone, Unchanged, false}},
+		{"1.0", "2.0", VersionDiff{ChangeMajor, Upgrade, false}},
+		{"2.0", "1.9.9", VersionDiff{ChangeMajor, Downgrade, false}},
+		{"1.2", "1.3.0", VersionDiff{ChangeMinor, Upgrade, false}},
+		{"1.2.3", "1.2.4", VersionDiff{ChangePatch, Upgrade, false}},
+		{"1.2.3.4", "1.2.3.5", VersionDiff{ChangePatch, Upgrade, false}},
+		{"1.2.3", "1.2.3.0.1", VersionDiff{ChangePatch, Upgrade, false}},
+		{"1.9", "2.0.dev1", VersionDiff{ChangeMajor, Upgrade, true}},
+		{"2.0rc1", "2.0", VersionDiff{ChangePre, Upgrade, true}},
+		{"2.0b1", "2.0rc1", VersionDiff{ChangePre, Upgrade, false}},
+		{"2.0rc2", "2.0rc1", VersionDiff{ChangePre, Downgrade, false}},
+		{"1.0", "1.0.post1", VersionDiff{ChangePost, Upgrade, false}},
+		{"1.0.post1", "1.0.post1.dev1", VersionDiff{ChangeDev, Downgrade, true}},
+		{"1.0a1.dev1", "1.0a1.dev2", VersionDiff{ChangeDev, Upgrade, false}},
+		{"1.0+cu121", "1.0+cpu", VersionDiff{ChangeLocal, Downgrade, false}},
+		{"1.0", "1.0+local", VersionDiff{ChangeLocal, Upgrade, false}},
+		{"2023.1", "1!1.0", VersionDiff{ChangeEpoch, Upgrade, false}},
+		{"99999999999999999998", "99999999999999999999", VersionDiff{ChangeMajor, Upgrade, false}},
+		{"1.0.macosx-10.5", "1.0", VersionDiff{ChangeUnknown, Upgrade, false}},
+		{"french toast", "french toast", VersionDiff{ChangeUnknown, Unchanged, false}},
+	}
+
+	for _, c := range diffCases {
+		t.Run(c.a+"->"+c.b, func(t *testing.T) {
+			a, _ := Parse(c.a)
+			b, _ := Parse(c.b)
+			if d := Diff(a, b); d != c.expected {
+				t.Errorf("%v(actual) != %v(expected)", d, c.expected)
+			}
+		})
+	}
+}
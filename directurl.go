@@ -0,0 +1,322 @@
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// DirectURL is the origin of a distribution installed from a URL instead of an index, which pip
+// records in direct_url.json of dist-info, for details: https://peps.python.org/pep-0610/. Exactly
+// one of VCSInfo, ArchiveInfo and DirInfo is set.
+type DirectURL struct {
+	// URL is the location without the VCS prefix, the revision and the fragment.
+	URL          string       `json:"url"`
+	Subdirectory string       `json:"subdirectory,omitempty"`
+	VCSInfo      *VCSInfo     `json:"vcs_info,omitempty"`
+	ArchiveInfo  *ArchiveInfo `json:"archive_info,omitempty"`
+	DirInfo      *DirInfo     `json:"dir_info,omitempty"`
+}
+
+// VCSInfo describes a VCS repository, CommitID is the resolved revision which is only known after
+// checking out, it's empty if the DirectURL is parsed from a requirement.
+type VCSInfo struct {
+	VCS               string `json:"vcs"`
+	RequestedRevision string `json:"requested_revision,omitempty"`
+	CommitID          string `json:"commit_id,omitempty"`
+}
+
+// ArchiveInfo describes a source or wheel archive, Hashes maps hash algorithms to hex digests, and
+// Hash is the deprecated form of a hash like 'sha256=<digest>'.
+type ArchiveInfo struct {
+	Hash   string            `json:"hash,omitempty"`
+	Hashes map[string]string `json:"hashes,omitempty"`
+}
+
+// DirInfo describes a local directory.
+type DirInfo struct {
+	Editable bool `json:"editable,omitempty"`
+}
+
+// vcs names and the schemes allowed after 'vcs+', the same as pip.
+var vcsSchemes = map[string]*Set{
+	"git": NewSet("http", "https", "ssh", "git", "file"),
+	"hg":  NewSet("http", "https", "ssh", "static-http", "file"),
+	"svn": NewSet("http", "https", "ssh", "svn", "file"),
+	"bzr": NewSet("http", "https", "ssh", "sftp", "ftp", "lp", "file"),
+}
+
+// hash fragments of archive URLs, see https://github.com/pypa/pip/blob/23.0.1/src/pip/_internal/models/link.py#L171.
+var hashFragmentRe = regexp.MustCompile(`^(sha1|sha224|sha384|sha256|sha512|md5)=([a-fA-F0-9]+)$`)
+
+// user info made of environment variables which is kept in direct_url.json, the same as pip.
+var envVarUserinfoRe = regexp.MustCompile(`^\$\{[A-Za-z0-9-_]+\}(:\$\{[A-Za-z0-9-_]+\})?$`)
+
+// windows paths starting with a drive letter, such as 'C:\project'.
+var drivePathRe = regexp.MustCompile(`^[A-Za-z]:[\\/]`)
+
+// ParseDirectURL parses the URL of a direct reference such as the one after '@' in a requirement:
+//
+//   - VCS URLs like 'git+https://host/repo.git@v1.2#subdirectory=pkg'
+//   - archive URLs like 'https://host/pkg-1.0.tar.gz#sha256=<digest>'
+//   - local directories like 'file:///home/user/project' or an absolute path such as 'C:\project'
+//
+// The 'subdirectory' fragment is supported by all kinds, and 'egg' fragments are ignored. A file URL
+// is treated as a directory unless it has the extension of a distribution. The user info is kept in
+// URL, it's removed by RedactedURL when writing direct_url.json.
+func ParseDirectURL(rawURL string) (*DirectURL, error) {
+	location := rawURL
+	switch {
+	case strings.HasPrefix(location, "/"):
+		location = "file://" + location
+	case drivePathRe.MatchString(location):
+		location = "file:///" + strings.ReplaceAll(location, `\`, "/")
+	}
+
+	// the user info is split off since url.Parse rejects environment variables like '${USER}'
+	userinfo, location := splitUserinfo(location)
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, &DirectURLError{URL: rawURL, Kind: ErrInvalidDirectURL, Err: err}
+	}
+	switch len(u.Scheme) {
+	case 0:
+		return nil, &DirectURLError{URL: rawURL, Kind: ErrInvalidDirectURL, Err: fmt.Errorf("missing scheme")}
+	case 1:
+		return nil, &DirectURLError{URL: rawURL, Kind: ErrInvalidDirectURL, Err: fmt.Errorf("unsupported scheme '%s'", u.Scheme)}
+	}
+
+	d := new(DirectURL)
+	var hashes []string
+	for _, item := range strings.Split(u.Fragment, "&") {
+		switch {
+		case strings.HasPrefix(item, "subdirectory="):
+			d.Subdirectory = strings.TrimPrefix(item, "subdirectory=")
+		case hashFragmentRe.MatchString(item):
+			hashes = append(hashes, item)
+		}
+	}
+	u.Fragment, u.RawFragment = "", ""
+
+	if i := strings.IndexByte(u.Scheme, '+'); i >= 0 {
+		vcs, scheme := u.Scheme[:i], u.Scheme[i+1:]
+		if schemes, ok := vcsSchemes[vcs]; !ok || !schemes.Contains(scheme) {
+			return nil, &DirectURLError{URL: rawURL, Kind: ErrInvalidDirectURL, Err: fmt.Errorf("unsupported scheme '%s'", u.Scheme)}
+		}
+
+		d.VCSInfo = &VCSInfo{VCS: vcs}
+		u.Scheme = scheme
+		// the revision follows the last '@' of path, an '@' before it is in the user info
+		if at := strings.LastIndexByte(u.Path, '@'); at >= 0 {
+			d.VCSInfo.RequestedRevision = u.Path[at+1:]
+			u.Path, u.RawPath = u.Path[:at], ""
+		}
+		d.URL = joinUserinfo(u.String(), userinfo)
+		return d, nil
+	}
+
+	_, ext := splitFilename(path.Base(u.Path))
+	if u.Scheme == "file" && !StandardExt.Contains(strings.ToLower(ext)) {
+		d.DirInfo = new(DirInfo)
+		d.URL = joinUserinfo(u.String(), userinfo)
+		return d, nil
+	}
+
+	d.ArchiveInfo = new(ArchiveInfo)
+	for _, hash := range hashes {
+		algorithm, digest := splitHash(hash)
+		if d.ArchiveInfo.Hash == "" {
+			d.ArchiveInfo.Hash = algorithm + "=" + digest
+			d.ArchiveInfo.Hashes = make(map[string]string)
+		}
+		d.ArchiveInfo.Hashes[algorithm] = digest
+	}
+	d.URL = joinUserinfo(u.String(), userinfo)
+
+	return d, nil
+}
+
+// splitUserinfo splits the user info off the netloc of URL s, the user info is empty if there is none.
+func splitUserinfo(s string) (string, string) {
+	start := strings.Index(s, "://")
+	if start < 0 {
+		return "", s
+	}
+	start += len("://")
+	end := len(s)
+	if i := strings.IndexAny(s[start:], "/?#"); i >= 0 {
+		end = start + i
+	}
+
+	at := strings.LastIndexByte(s[start:end], '@')
+	if at < 0 {
+		return "", s
+	}
+
+	return s[start : start+at], s[:start] + s[start+at+1:]
+}
+
+// joinUserinfo adds the user info to the netloc of URL s.
+func joinUserinfo(s, userinfo string) string {
+	if userinfo == "" {
+		return s
+	}
+	i := strings.Index(s, "://") + len("://")
+
+	return s[:i] + userinfo + "@" + s[i:]
+}
+
+// RedactedURL returns URL without the user info as required by pep-610, except for environment
+// variables like '${USER}:${PASSWORD}' and the 'git' user of git repositories, the same as pip.
+func (d *DirectURL) RedactedURL() string {
+	userinfo, redacted := splitUserinfo(d.URL)
+	if userinfo == "" || envVarUserinfoRe.MatchString(userinfo) || d.VCSInfo != nil && d.VCSInfo.VCS == "git" && userinfo == "git" {
+		return d.URL
+	}
+
+	return redacted
+}
+
+// DirectURL returns the direct reference of requirement, an error of ErrInvalidDirectURL is returned
+// if the requirement has no URL.
+func (r *Requirement) DirectURL() (*DirectURL, error) {
+	if r.URL == "" {
+		return nil, &DirectURLError{Kind: ErrInvalidDirectURL, Err: fmt.Errorf("requirement '%s' has no URL", r)}
+	}
+
+	return ParseDirectURL(r.URL)
+}
+
+// String returns the URL of direct reference for requirements, which is parsed back by
+// ParseDirectURL, e.g. 'git+https://host/repo.git@v1.2#subdirectory=pkg'.
+func (d *DirectURL) String() string {
+	s := d.URL
+	var fragments []string
+	switch {
+	case d.VCSInfo != nil:
+		s = d.VCSInfo.VCS + "+" + s
+		if revision := d.VCSInfo.CommitID; revision != "" || d.VCSInfo.RequestedRevision != "" {
+			if revision == "" {
+				revision = d.VCSInfo.RequestedRevision
+			}
+			s += "@" + revision
+		}
+	case d.ArchiveInfo != nil:
+		if hash := d.ArchiveInfo.hash(); hash != "" {
+			fragments = append(fragments, hash)
+		}
+	}
+	if d.Subdirectory != "" {
+		fragments = append(fragments, "subdirectory="+d.Subdirectory)
+	}
+	if len(fragments) != 0 {
+		s += "#" + strings.Join(fragments, "&")
+	}
+
+	return s
+}
+
+// splitHash splits a hash like 'sha256=<digest>' into the algorithm and the digest in lower case.
+func splitHash(hash string) (string, string) {
+	eq := strings.IndexByte(hash, '=')
+	return hash[:eq], strings.ToLower(hash[eq+1:])
+}
+
+// hash returns a hash in the form of 'sha256=<digest>', the strongest one is preferred.
+func (a *ArchiveInfo) hash() string {
+	for _, algorithm := range []string{"sha512", "sha384", "sha256", "sha224", "sha1", "md5"} {
+		if digest, ok := a.Hashes[algorithm]; ok {
+			return algorithm + "=" + digest
+		}
+	}
+
+	return a.Hash
+}
+
+// UnmarshalJSON decodes direct_url.json and validates it, digests are converted into lower case and
+// the deprecated hash of archive is also added to hashes if it's missing there.
+func (d *DirectURL) UnmarshalJSON(data []byte) error {
+	type plain DirectURL
+	var decoded plain
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	direct := DirectURL(decoded)
+	if err := direct.validate(); err != nil {
+		return err
+	}
+	if a := direct.ArchiveInfo; a != nil {
+		for algorithm, digest := range a.Hashes {
+			a.Hashes[algorithm] = strings.ToLower(digest)
+		}
+		if a.Hash != "" {
+			algorithm, digest := splitHash(a.Hash)
+			a.Hash = algorithm + "=" + digest
+			if a.Hashes == nil {
+				a.Hashes = make(map[string]string)
+			}
+			a.Hashes[algorithm] = digest
+		}
+	}
+	*d = direct
+
+	return nil
+}
+
+// MarshalJSON encodes d as direct_url.json after validating it, the URL is redacted by RedactedURL.
+func (d *DirectURL) MarshalJSON() ([]byte, error) {
+	if err := d.validate(); err != nil {
+		return nil, err
+	}
+
+	type plain DirectURL
+	redacted := plain(*d)
+	redacted.URL = d.RedactedURL()
+	return json.Marshal(&redacted)
+}
+
+func (d *DirectURL) validate() error {
+	fail := func(format string, args ...interface{}) error {
+		return &DirectURLError{URL: d.URL, Kind: ErrInvalidDirectURL, Err: fmt.Errorf(format, args...)}
+	}
+
+	if d.URL == "" {
+		return fail("missing url")
+	}
+	n := 0
+	for _, set := range []bool{d.VCSInfo != nil, d.ArchiveInfo != nil, d.DirInfo != nil} {
+		if set {
+			n++
+		}
+	}
+	if n != 1 {
+		return fail("exactly one of vcs_info, archive_info and dir_info is expected")
+	}
+
+	switch {
+	case d.VCSInfo != nil:
+		if _, ok := vcsSchemes[d.VCSInfo.VCS]; !ok {
+			return fail("unsupported vcs '%s'", d.VCSInfo.VCS)
+		}
+	case d.ArchiveInfo != nil:
+		if hash := d.ArchiveInfo.Hash; hash != "" {
+			if !hashFragmentRe.MatchString(hash) {
+				return fail("invalid hash '%s'", hash)
+			}
+			algorithm, digest := splitHash(hash)
+			if other, ok := d.ArchiveInfo.Hashes[algorithm]; ok && !strings.EqualFold(other, digest) {
+				return fail("hash '%s' mismatches hashes", hash)
+			}
+		}
+	case d.DirInfo != nil:
+		if !strings.HasPrefix(d.URL, "file:") {
+			return fail("directory url should be a file url")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,285 @@
+package version
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RequirementsEditor edits the requirements of a requirements file in place, the content is kept
+// byte-identical except for the edited spans, so comments, ordering, continuations and the layout of
+// hashes are preserved. The files included by '-r' and '-c' are not followed.
+type RequirementsEditor struct {
+	content string
+	entries []*RequirementEntry
+}
+
+// RequirementEntry is a requirement line of a RequirementsEditor, its edits take effect in Bytes of
+// the editor.
+type RequirementEntry struct {
+	req *FileRequirement
+
+	// [specStart, specEnd) is the span of specifiers in content, specStart is -1 if the specifiers
+	// can't be edited, e.g. for a URL requirement.
+	specStart, specEnd int
+	spec               *SpecifierSet
+
+	hashes []*hashOption
+	added  []string
+	// end is the end of the last token of the line in content, where the added hashes are inserted.
+	end int
+}
+
+// hashOption is a '--hash' option of a requirement line.
+type hashOption struct {
+	value string
+	// [start, end) is the span of option in content, and prevEnd is the end of the previous token,
+	// so that removing [prevEnd, end) also removes the whitespaces and continuations before it.
+	start, end, prevEnd int
+	equal               bool
+	removed             bool
+}
+
+// NewRequirementsEditor parses content of the requirements file named name, the lines of options
+// are kept intact.
+func NewRequirementsEditor(name string, content []byte) (*RequirementsEditor, error) {
+	e := &RequirementsEditor{content: string(content)}
+
+	for _, line := range splitRequirementsLines(e.content) {
+		entry, err := e.parseLine(name, line)
+		if err != nil {
+			return nil, &RequirementsError{File: name, Line: line.number, Kind: ErrInvalidRequirementsFile, Err: err}
+		}
+		if entry != nil {
+			e.entries = append(e.entries, entry)
+		}
+	}
+
+	return e, nil
+}
+
+func (e *RequirementsEditor) parseLine(name string, line *requirementsLine) (*RequirementEntry, error) {
+	args, options := line.args()
+	opts, err := parseRequirementsOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		return nil, nil
+	}
+
+	raw := line.requirement(args)
+	text := expandEnv(raw)
+	entry := &RequirementEntry{specStart: -1, end: line.end(len(line.text))}
+
+	// the specifiers are located for a pep-508 requirement without environment variables
+	var version string
+	p := &requirementParser{s: text}
+	r, err := p.requirement()
+	if err == nil {
+		if r.URL == "" && text == raw {
+			start := args[0].start
+			if a, b, ok := line.span(start+p.specStart, start+p.specEnd); ok {
+				entry.specStart, entry.specEnd = a, b
+			}
+		}
+	} else if r, version, err = parseRequirementLine(text, false); err != nil {
+		return nil, err
+	}
+	entry.req = &FileRequirement{Requirement: r, Version: version, File: name, Line: line.number}
+
+	for _, opt := range opts {
+		switch opt.name {
+		case "--hash":
+			prev := opt.start
+			for prev > 0 && isRequirementsSpace(line.text[prev-1]) {
+				prev--
+			}
+			entry.hashes = append(entry.hashes, &hashOption{
+				value:   opt.value,
+				start:   line.offsets[opt.start],
+				end:     line.end(opt.end),
+				prevEnd: line.end(prev),
+				equal:   strings.HasPrefix(line.text[opt.start:opt.end], "--hash="),
+			})
+		}
+	}
+
+	return entry, nil
+}
+
+// end returns the offset in content after the byte before i of the logical line.
+func (l *requirementsLine) end(i int) int {
+	return l.offsets[i-1] + 1
+}
+
+// span maps [start, end) of the logical line to content, it reports false if the span isn't
+// contiguous in content, i.e. it crosses a continuation.
+func (l *requirementsLine) span(start, end int) (int, int, bool) {
+	if start == end {
+		return l.end(start), l.end(start), true
+	}
+	if l.offsets[end-1]-l.offsets[start] != end-1-start {
+		return 0, 0, false
+	}
+
+	return l.offsets[start], l.end(end), true
+}
+
+// Requirements returns the requirement lines in order.
+func (e *RequirementsEditor) Requirements() []*RequirementEntry {
+	return append([]*RequirementEntry(nil), e.entries...)
+}
+
+// Find returns the requirement lines of a package, names are compared after canonicalization.
+func (e *RequirementsEditor) Find(name string) []*RequirementEntry {
+	name = CanonicalizePackage(name)
+
+	var entries []*RequirementEntry
+	for _, entry := range e.entries {
+		if entry.req.Requirement.Name != "" && CanonicalizePackage(entry.req.Requirement.Name) == name {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// Bytes returns the content with all the edits applied.
+func (e *RequirementsEditor) Bytes() []byte {
+	type edit struct {
+		start, end int
+		text       string
+	}
+
+	var edits []edit
+	for _, entry := range e.entries {
+		if entry.spec != nil {
+			edits = append(edits, edit{entry.specStart, entry.specEnd, entry.spec.String()})
+		}
+
+		separator, option := " ", "--hash="
+		for _, h := range entry.hashes {
+			if h.removed {
+				edits = append(edits, edit{h.prevEnd, h.end, ""})
+			}
+			separator, option = e.content[h.prevEnd:h.start], "--hash "
+			if h.equal {
+				option = "--hash="
+			}
+		}
+		var added strings.Builder
+		for _, hash := range entry.added {
+			added.WriteString(separator + option + hash)
+		}
+		if added.Len() != 0 {
+			edits = append(edits, edit{entry.end, entry.end, added.String()})
+		}
+	}
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].start < edits[j].start
+	})
+
+	var b strings.Builder
+	offset := 0
+	for _, edit := range edits {
+		b.WriteString(e.content[offset:edit.start])
+		b.WriteString(edit.text)
+		offset = edit.end
+	}
+	b.WriteString(e.content[offset:])
+
+	return []byte(b.String())
+}
+
+// Requirement returns the requirement with the edited specifiers.
+func (entry *RequirementEntry) Requirement() *Requirement {
+	r := *entry.req.Requirement
+	if entry.spec != nil {
+		r.Specifier = entry.spec
+	}
+
+	return &r
+}
+
+// Line returns the line number where the requirement starts.
+func (entry *RequirementEntry) Line() int {
+	return entry.req.Line
+}
+
+// Hashes returns the hashes after edits.
+func (entry *RequirementEntry) Hashes() []string {
+	var hashes []string
+	for _, h := range entry.hashes {
+		if !h.removed {
+			hashes = append(hashes, h.value)
+		}
+	}
+
+	return append(hashes, entry.added...)
+}
+
+// SetSpecifier replaces the version specifiers, it fails with ErrNotEditable if the requirement is a
+// URL or a local path, or if the specifiers are split by a line continuation.
+func (entry *RequirementEntry) SetSpecifier(set *SpecifierSet) error {
+	if entry.specStart < 0 {
+		return &RequirementsError{File: entry.req.File, Line: entry.req.Line, Kind: ErrNotEditable,
+			Err: fmt.Errorf("requirement '%s'", entry.req.Requirement)}
+	}
+	entry.spec = set
+
+	return nil
+}
+
+// SetVersion pins the requirement to v by replacing the specifiers with '==<v>'.
+func (entry *RequirementEntry) SetVersion(v *Version) error {
+	set, err := ParseSpecifierSet(OpEqual + v.Complete())
+	if err != nil {
+		return err
+	}
+
+	return entry.SetSpecifier(set)
+}
+
+// AddHash appends a '--hash' option in the same layout as the last existing one, hash is in the form
+// of '<algorithm>:<digest>'. Adding an existing hash has no effect.
+func (entry *RequirementEntry) AddHash(hash string) error {
+	if _, err := parseRequirementsOptions([]requirementsToken{{text: "--hash=" + hash}}); err != nil {
+		return err
+	}
+
+	for _, h := range entry.hashes {
+		if h.value == hash && h.removed {
+			h.removed = false
+			return nil
+		}
+	}
+	for _, h := range entry.Hashes() {
+		if h == hash {
+			return nil
+		}
+	}
+	entry.added = append(entry.added, hash)
+
+	return nil
+}
+
+// RemoveHash removes the '--hash' options of hash together with the whitespaces and continuations
+// before them, it reports false if there is no such hash.
+func (entry *RequirementEntry) RemoveHash(hash string) bool {
+	found := false
+	for _, h := range entry.hashes {
+		if h.value == hash && !h.removed {
+			h.removed, found = true, true
+		}
+	}
+	for i := 0; i < len(entry.added); i++ {
+		if entry.added[i] == hash {
+			entry.added = append(entry.added[:i], entry.added[i+1:]...)
+			i--
+			found = true
+		}
+	}
+
+	return found
+}
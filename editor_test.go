@@ -0,0 +1,178 @@
+package version
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const editorContent = `# pinned by pip-compile
+--index-url https://pypi.example.com/simple
+
+attrs==22.2.0 \
+    --hash=sha256:aaaa \
+    --hash=sha256:bbbb
+    # via -r requirements.in
+requests[socks] >= 2.8.1 , < 3 ; python_version >= "3.7"  # keep
+six  # no version
+numpy==1.24.2 --hash sha256:cccc
+idna>=2 \
+,<4
+./vendor/pkg#egg=pkg
+`
+
+func TestRequirementsEditor(t *testing.T) {
+	editor, err := NewRequirementsEditor("requirements.txt", []byte(editorContent))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(editor.Bytes()) != editorContent {
+		t.Errorf("content without edits should be identical")
+	}
+
+	var names []string
+	for _, entry := range editor.Requirements() {
+		names = append(names, entry.Requirement().Name)
+	}
+	if strings.Join(names, " ") != "attrs requests six numpy idna pkg" {
+		t.Errorf("%v are unexpected requirements", names)
+	}
+
+	attrs := editor.Find("ATTRS")[0]
+	v, _ := ParseVersion("23.1.0")
+	if err := attrs.SetVersion(v); err != nil {
+		t.Fatal(err)
+	}
+	attrs.RemoveHash("sha256:aaaa")
+	if err := attrs.AddHash("sha256:dddd"); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := editor.Find("requests")[0]
+	set, _ := ParseSpecifierSet(">=2.31,<3")
+	if err := requests.SetSpecifier(set); err != nil {
+		t.Fatal(err)
+	}
+
+	six := editor.Find("six")[0]
+	set, _ = ParseSpecifierSet("==1.16.0")
+	if err := six.SetSpecifier(set); err != nil {
+		t.Fatal(err)
+	}
+	if err := six.AddHash("sha256:eeee"); err != nil {
+		t.Fatal(err)
+	}
+
+	numpy := editor.Find("numpy")[0]
+	if !numpy.RemoveHash("sha256:cccc") || numpy.RemoveHash("sha256:cccc") {
+		t.Errorf("sha256:cccc should be removed once")
+	}
+	if err := numpy.AddHash("sha256:ffff"); err != nil {
+		t.Fatal(err)
+	}
+	if err := numpy.AddHash("md5:ffff"); err == nil {
+		t.Errorf("md5:ffff should be an invalid hash")
+	}
+
+	if err := editor.Find("idna")[0].SetVersion(v); !errors.Is(err, ErrNotEditable) {
+		t.Errorf("specifiers split by a continuation should not be editable, %v", err)
+	}
+	var requirementsErr *RequirementsError
+	if err := editor.Find("pkg")[0].SetVersion(v); !errors.Is(err, ErrNotEditable) || !errors.As(err, &requirementsErr) || requirementsErr.Line != 13 {
+		t.Errorf("specifiers of a local path should not be editable, %v", err)
+	}
+
+	expected := `# pinned by pip-compile
+--index-url https://pypi.example.com/simple
+
+attrs==23.1.0 \
+    --hash=sha256:bbbb \
+    --hash=sha256:dddd
+    # via -r requirements.in
+requests[socks] <3,>=2.31 ; python_version >= "3.7"  # keep
+six==1.16.0 --hash=sha256:eeee  # no version
+numpy==1.24.2 --hash sha256:ffff
+idna>=2 \
+,<4
+./vendor/pkg#egg=pkg
+`
+	if actual := string(editor.Bytes()); actual != expected {
+		t.Errorf("%s(actual) != %s(expected)", actual, expected)
+	}
+
+	if hashes := strings.Join(attrs.Hashes(), " "); hashes != "sha256:bbbb sha256:dddd" {
+		t.Errorf("%s(actual) != sha256:bbbb sha256:dddd(expected)", hashes)
+	}
+	if r := attrs.Requirement().String(); r != "attrs==23.1.0" {
+		t.Errorf("%s(actual) != attrs==23.1.0(expected)", r)
+	}
+}
+
+func TestRequirementsEditorHashes(t *testing.T) {
+	content := "attrs==22.2.0 \\\n    --hash=sha256:aaaa \\\n    --hash=sha256:bbbb\nsix\n"
+	editor, _ := NewRequirementsEditor("requirements.txt", []byte(content))
+	attrs := editor.Find("attrs")[0]
+
+	attrs.RemoveHash("sha256:bbbb")
+	if actual := string(editor.Bytes()); actual != "attrs==22.2.0 \\\n    --hash=sha256:aaaa\nsix\n" {
+		t.Errorf("%q is unexpected after removing the last hash", actual)
+	}
+
+	attrs.RemoveHash("sha256:aaaa")
+	if actual := string(editor.Bytes()); actual != "attrs==22.2.0\nsix\n" {
+		t.Errorf("%q is unexpected after removing all hashes", actual)
+	}
+
+	attrs.AddHash("sha256:cccc")
+	attrs.AddHash("sha256:aaaa")
+	if actual := string(editor.Bytes()); actual != "attrs==22.2.0 \\\n    --hash=sha256:aaaa \\\n    --hash=sha256:cccc\nsix\n" {
+		t.Errorf("%q is unexpected after adding hashes", actual)
+	}
+}
+
+func TestRequirementsEditorOptions(t *testing.T) {
+	content := "foo==1.0 --pre --hash=sha256:aaaa -i https://example.com/simple\nbar>=2 --global-option=--quiet\n"
+	editor, err := NewRequirementsEditor("requirements.txt", []byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	foo := editor.Find("foo")[0]
+	v, _ := ParseVersion("1.1")
+	if err := foo.SetVersion(v); err != nil {
+		t.Fatal(err)
+	}
+	foo.RemoveHash("sha256:aaaa")
+	foo.AddHash("sha256:bbbb")
+
+	expected := "foo==1.1 --pre -i https://example.com/simple --hash=sha256:bbbb\nbar>=2 --global-option=--quiet\n"
+	if actual := string(editor.Bytes()); actual != expected {
+		t.Errorf("%q(actual) != %q(expected)", actual, expected)
+	}
+}
+
+func TestRequirementsEditorWhitespaces(t *testing.T) {
+	content := "pkg  >=1 ;  platform_version == 'a  b'\n"
+	editor, err := NewRequirementsEditor("requirements.txt", []byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry := editor.Find("pkg")[0]
+	set, _ := ParseSpecifierSet(">=2")
+	if err := entry.SetSpecifier(set); err != nil {
+		t.Fatal(err)
+	}
+	if actual := entry.Requirement().String(); actual != `pkg>=2; platform_version == "a  b"` {
+		t.Errorf("%s(actual) != pkg>=2; platform_version == \"a  b\"(expected)", actual)
+	}
+	if actual := string(editor.Bytes()); actual != "pkg  >=2 ;  platform_version == 'a  b'\n" {
+		t.Errorf("%q is unexpected after setting specifiers", actual)
+	}
+}
+
+func TestRequirementsEditorError(t *testing.T) {
+	if _, err := NewRequirementsEditor("requirements.txt", []byte("six\nrequests>=")); err == nil {
+		t.Errorf("invalid requirement should be an error")
+	}
+}
@@ -0,0 +1,156 @@
+package version
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Versions are encoded as their complete strings for all encodings, so Parse(Complete()) always
+// results in an equal version and an identical one for canonical versions.
+
+func (v *Version) MarshalText() ([]byte, error) {
+	return []byte(v.Complete()), nil
+}
+
+func (v *Version) UnmarshalText(text []byte) error {
+	parsed, err := ParseVersion(string(text))
+	if err != nil {
+		return err
+	}
+
+	*v = *parsed
+	return nil
+}
+
+func (v *Version) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.Complete())
+}
+
+func (v *Version) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	return v.UnmarshalText([]byte(s))
+}
+
+// Value implements driver.Valuer, a version is stored as a string.
+func (v *Version) Value() (driver.Value, error) {
+	return v.Complete(), nil
+}
+
+// Scan implements sql.Scanner, it accepts a string or bytes, use a nullable column with a pointer.
+func (v *Version) Scan(src interface{}) error {
+	text, err := scanText(src)
+	if err != nil {
+		return err
+	}
+
+	return v.UnmarshalText(text)
+}
+
+func (v *Version) GobEncode() ([]byte, error) {
+	return v.MarshalText()
+}
+
+func (v *Version) GobDecode(data []byte) error {
+	return v.UnmarshalText(data)
+}
+
+func (v *LegacyVersion) MarshalText() ([]byte, error) {
+	return []byte(v.Complete()), nil
+}
+
+func (v *LegacyVersion) UnmarshalText(text []byte) error {
+	parsed, err := ParseLegacyVersion(string(text))
+	if err != nil {
+		return err
+	}
+
+	*v = *parsed
+	return nil
+}
+
+func (v *LegacyVersion) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.Complete())
+}
+
+func (v *LegacyVersion) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	return v.UnmarshalText([]byte(s))
+}
+
+// Value implements driver.Valuer, a version is stored as a string.
+func (v *LegacyVersion) Value() (driver.Value, error) {
+	return v.Complete(), nil
+}
+
+// Scan implements sql.Scanner, it accepts a string or bytes, use a nullable column with a pointer.
+func (v *LegacyVersion) Scan(src interface{}) error {
+	text, err := scanText(src)
+	if err != nil {
+		return err
+	}
+
+	return v.UnmarshalText(text)
+}
+
+func (v *LegacyVersion) GobEncode() ([]byte, error) {
+	return v.MarshalText()
+}
+
+func (v *LegacyVersion) GobDecode(data []byte) error {
+	return v.UnmarshalText(data)
+}
+
+func scanText(src interface{}) ([]byte, error) {
+	switch s := src.(type) {
+	case string:
+		return []byte(s), nil
+	case []byte:
+		return s, nil
+	case nil:
+		return nil, fmt.Errorf("cannot scan NULL into a version")
+	default:
+		return nil, fmt.Errorf("cannot scan %T into a version", src)
+	}
+}
+
+// MarshalText encodes a stage as its string such as 'rc1'.
+func (t *Stage) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText decodes a stage from a name followed by a number, alternative spellings of names
+// are normalized and the number can be omitted, e.g. 'alpha' is decoded as 'a0'.
+func (t *Stage) UnmarshalText(text []byte) error {
+	s := string(text)
+	i := strings.IndexAny(s, "0123456789")
+	if i < 0 {
+		i = len(s)
+	}
+
+	name := canonicalLetter(s[:i])
+	if !NewSet("a", "b", "rc", "post", "dev").Contains(name) {
+		return fmt.Errorf("not a valid stage '%s'", s)
+	}
+	var number int64
+	if i < len(s) {
+		n, err := strconv.ParseInt(s[i:], 10, 64)
+		if err != nil {
+			return fmt.Errorf("not a valid stage '%s', %w", s, err)
+		}
+		number = n
+	}
+
+	t.Name, t.Number = name, number
+	return nil
+}
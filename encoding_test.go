@@ -0,0 +1,175 @@
+package version
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestVersionEncoding(t *testing.T) {
+	var canonicalVersions = []string{
+		"1.0",
+		"1.0.0",
+		"1!2.0rc1.post2.dev3+ubuntu.1",
+		"0.1.dev0",
+	}
+
+	for _, version := range canonicalVersions {
+		t.Run(version, func(t *testing.T) {
+			v, err := ParseVersion(version)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			data, err := json.Marshal(struct {
+				V *Version `json:"v"`
+			}{v})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if expected := `{"v":"` + version + `"}`; string(data) != expected {
+				t.Errorf("%s(actual) != %s(expected)", data, expected)
+			}
+			var decoded struct {
+				V *Version `json:"v"`
+			}
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Error(err)
+				return
+			}
+			if !reflect.DeepEqual(decoded.V, v) || decoded.V.Complete() != version {
+				t.Errorf("%s(actual) != %s(expected)", decoded.V, v)
+			}
+
+			var buf bytes.Buffer
+			if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+				t.Error(err)
+				return
+			}
+			gobDecoded := new(Version)
+			if err := gob.NewDecoder(&buf).Decode(gobDecoded); err != nil {
+				t.Error(err)
+				return
+			}
+			if !reflect.DeepEqual(gobDecoded, v) {
+				t.Errorf("%s(actual) != %s(expected)", gobDecoded, v)
+			}
+
+			value, err := v.Value()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			scanned := new(Version)
+			if err := scanned.Scan([]byte(value.(string))); err != nil {
+				t.Error(err)
+				return
+			}
+			if !reflect.DeepEqual(scanned, v) {
+				t.Errorf("%s(actual) != %s(expected)", scanned, v)
+			}
+		})
+	}
+
+	var v Version
+	if err := json.Unmarshal([]byte(`"french toast"`), &v); err == nil {
+		t.Error("'french toast' should be an invalid version")
+	}
+	if err := v.Scan(nil); err == nil {
+		t.Error("NULL should not be scanned into a version")
+	}
+	if err := v.Scan("V1.0-ALPHA"); err != nil || v.Complete() != "1.0a0" {
+		t.Errorf("%s(actual) != 1.0a0(expected), %v", v.Complete(), err)
+	}
+}
+
+func TestLegacyVersionEncoding(t *testing.T) {
+	v, err := ParseLegacyVersion("1.0.macosx-10.5")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	decoded := new(LegacyVersion)
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Error(err)
+		return
+	}
+	if !reflect.DeepEqual(decoded, v) {
+		t.Errorf("%s(actual) != %s(expected)", decoded, v)
+	}
+
+	scanned := new(LegacyVersion)
+	if err := scanned.Scan("1.0.macosx-10.5"); err != nil || !scanned.Equal(v) {
+		t.Errorf("%s(actual) != %s(expected), %v", scanned, v, err)
+	}
+}
+
+func TestStageEncoding(t *testing.T) {
+	var stageCases = []struct {
+		text     string
+		expected *Stage
+	}{
+		{"rc1", &Stage{"rc", 1}},
+		{"alpha", &Stage{"a", 0}},
+		{"POST12", &Stage{"post", 12}},
+		{"dev", &Stage{"dev", 0}},
+		{"1", nil},
+		{"foo1", nil},
+	}
+
+	for _, c := range stageCases {
+		t.Run(c.text, func(t *testing.T) {
+			stage := new(Stage)
+			err := stage.UnmarshalText([]byte(c.text))
+			if (c.expected == nil) != (err != nil) {
+				t.Error(err)
+				return
+			}
+			if c.expected != nil && !reflect.DeepEqual(stage, c.expected) {
+				t.Errorf("%v(actual) != %v(expected)", stage, c.expected)
+			}
+		})
+	}
+
+	data, err := json.Marshal([]*Stage{{"rc", 1}, {"post", 2}})
+	if err != nil || string(data) != `["rc1","post2"]` {
+		t.Errorf("%s(actual) != [\"rc1\",\"post2\"](expected), %v", data, err)
+	}
+}
+
+func TestWheelEncoding(t *testing.T) {
+	whl, err := NewWheel("nupyprop-0.1.7-1-cp38-cp38-manylinux_2_17_x86_64.manylinux2014_x86_64.whl")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	data, err := json.Marshal(whl)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	expected := `{"filename":"nupyprop-0.1.7-1-cp38-cp38-manylinux_2_17_x86_64.manylinux2014_x86_64.whl","name":"nupyprop","version":"0.1.7","build_tag":"1","python_tags":["cp38"],"abi_tags":["cp38"],"platform_tags":["manylinux_2_17_x86_64","manylinux2014_x86_64"]}`
+	if string(data) != expected {
+		t.Errorf("%s(actual) != %s(expected)", data, expected)
+	}
+
+	decoded := new(Wheel)
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Error(err)
+		return
+	}
+	if !reflect.DeepEqual(decoded, whl) {
+		t.Errorf("%v(actual) != %v(expected)", decoded, whl)
+	}
+}
@@ -0,0 +1,253 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+)
+
+// sentinel kinds of errors, use errors.Is to check the kind of an error returned by this package.
+var (
+	ErrInvalidVersion          = errors.New("invalid version")
+	ErrNotCanonical            = errors.New("version not canonical")
+	ErrInvalidName             = errors.New("invalid package name")
+	ErrInvalidFilename         = errors.New("invalid filename")
+	ErrNameMismatch            = errors.New("package name mismatch")
+	ErrVersionNotFound         = errors.New("version not found")
+	ErrUnsupportedExt          = errors.New("unsupported file extension")
+	ErrNotExpressible          = errors.New("not expressible as a specifier set")
+	ErrInvalidSpecifier        = errors.New("invalid specifier")
+	ErrInvalidRequirement      = errors.New("invalid requirement")
+	ErrInvalidMarker           = errors.New("invalid marker")
+	ErrUndefinedComparison     = errors.New("undefined comparison")
+	ErrInvalidRequirementsFile = errors.New("invalid requirements file")
+	ErrIncludeCycle            = errors.New("cyclic include")
+	ErrInvalidDirectURL        = errors.New("invalid direct url")
+	ErrInvalidProfile          = errors.New("invalid profile")
+	ErrNotEditable             = errors.New("specifiers not editable")
+)
+
+// components of versions and filenames where an error occurs.
+const (
+	// version segments
+	ComponentEpoch   = "epoch"
+	ComponentRelease = "release"
+	ComponentPre     = "pre"
+	ComponentPost    = "post"
+	ComponentDev     = "dev"
+	ComponentLocal   = "local"
+
+	// filename segments, see https://peps.python.org/pep-0427/#file-name-convention
+	ComponentName      = "name"
+	ComponentVersion   = "version"
+	ComponentBuild     = "build"
+	ComponentPython    = "python"
+	ComponentABI       = "abi"
+	ComponentPlatform  = "platform"
+	ComponentExtension = "extension"
+)
+
+// VersionError describes a version which can't be parsed or built.
+type VersionError struct {
+	// Version is the input version, it's empty if the version is built from segments.
+	Version string
+	// Component is the failing segment, it's empty if the version is invalid as a whole.
+	Component string
+	Kind      error
+	// Err is the underlying error such as a *strconv.NumError, it can be nil.
+	Err error
+}
+
+func (e *VersionError) Error() string {
+	return formatError("version", e.Version, e.Component, e.Kind, e.Err)
+}
+
+func (e *VersionError) Unwrap() error {
+	return e.Err
+}
+
+func (e *VersionError) Is(target error) bool {
+	return target == e.Kind
+}
+
+// NameError describes an illegal package name.
+type NameError struct {
+	Name string
+	Kind error
+	Err  error
+}
+
+func (e *NameError) Error() string {
+	return formatError("package name", e.Name, "", e.Kind, e.Err)
+}
+
+func (e *NameError) Unwrap() error {
+	return e.Err
+}
+
+func (e *NameError) Is(target error) bool {
+	return target == e.Kind
+}
+
+// FilenameError describes a distribution filename which can't be parsed or doesn't match a package.
+type FilenameError struct {
+	Filename string
+	// Component is the failing segment, it's empty if the filename is invalid as a whole.
+	Component string
+	Kind      error
+	Err       error
+}
+
+func (e *FilenameError) Error() string {
+	return formatError("filename", e.Filename, e.Component, e.Kind, e.Err)
+}
+
+func (e *FilenameError) Unwrap() error {
+	return e.Err
+}
+
+func (e *FilenameError) Is(target error) bool {
+	return target == e.Kind
+}
+
+// RequirementError describes a dependency specifier which can't be parsed.
+type RequirementError struct {
+	Requirement string
+	// Pos is the byte offset in Requirement where parsing fails.
+	Pos  int
+	Kind error
+	Err  error
+}
+
+func (e *RequirementError) Error() string {
+	return formatError("requirement", e.Requirement, fmt.Sprintf("position %d", e.Pos), e.Kind, e.Err)
+}
+
+func (e *RequirementError) Unwrap() error {
+	return e.Err
+}
+
+func (e *RequirementError) Is(target error) bool {
+	return target == e.Kind
+}
+
+// MarkerError describes an environment marker which can't be parsed.
+type MarkerError struct {
+	Marker string
+	// Pos is the byte offset in Marker where parsing fails.
+	Pos  int
+	Kind error
+	Err  error
+}
+
+func (e *MarkerError) Error() string {
+	return formatError("marker", e.Marker, fmt.Sprintf("position %d", e.Pos), e.Kind, e.Err)
+}
+
+func (e *MarkerError) Unwrap() error {
+	return e.Err
+}
+
+func (e *MarkerError) Is(target error) bool {
+	return target == e.Kind
+}
+
+// RequirementsError describes a requirements file which can't be parsed.
+type RequirementsError struct {
+	File string
+	// Line is the line number where the error occurs, it's 0 if the file is invalid as a whole.
+	Line int
+	Kind error
+	Err  error
+}
+
+func (e *RequirementsError) Error() string {
+	var line string
+	if e.Line != 0 {
+		line = fmt.Sprintf("line %d", e.Line)
+	}
+	return formatError("requirements file", e.File, line, e.Kind, e.Err)
+}
+
+func (e *RequirementsError) Unwrap() error {
+	return e.Err
+}
+
+func (e *RequirementsError) Is(target error) bool {
+	return target == e.Kind
+}
+
+// SpecifierError describes a version specifier or a poetry constraint which can't be parsed.
+type SpecifierError struct {
+	Specifier string
+	Kind      error
+	Err       error
+}
+
+func (e *SpecifierError) Error() string {
+	return formatError("specifier", e.Specifier, "", e.Kind, e.Err)
+}
+
+func (e *SpecifierError) Unwrap() error {
+	return e.Err
+}
+
+func (e *SpecifierError) Is(target error) bool {
+	return target == e.Kind
+}
+
+// ProfileError describes a target profile which can't be created.
+type ProfileError struct {
+	// Profile is the name of profile, i.e. '<interpreter>-<platform>'.
+	Profile string
+	// Component is ComponentPython or ComponentPlatform.
+	Component string
+	Kind      error
+	Err       error
+}
+
+func (e *ProfileError) Error() string {
+	return formatError("profile", e.Profile, e.Component, e.Kind, e.Err)
+}
+
+func (e *ProfileError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ProfileError) Is(target error) bool {
+	return target == e.Kind
+}
+
+// DirectURLError describes a direct reference which can't be parsed or encoded.
+type DirectURLError struct {
+	URL  string
+	Kind error
+	Err  error
+}
+
+func (e *DirectURLError) Error() string {
+	return formatError("direct url", e.URL, "", e.Kind, e.Err)
+}
+
+func (e *DirectURLError) Unwrap() error {
+	return e.Err
+}
+
+func (e *DirectURLError) Is(target error) bool {
+	return target == e.Kind
+}
+
+func formatError(subject, input, component string, kind, err error) string {
+	msg := subject
+	if input != "" {
+		msg += fmt.Sprintf(" '%s'", input)
+	}
+	if component != "" {
+		msg += fmt.Sprintf(" (%s)", component)
+	}
+	msg += ": " + kind.Error()
+	if err != nil {
+		msg += ", " + err.Error()
+	}
+
+	return msg
+}
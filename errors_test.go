@@ -0,0 +1,123 @@
+package version
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVersionError(t *testing.T) {
+	var versionErrorCases = []struct {
+		version   string
+		component string
+	}{
+		{"french toast", ""},
+		{"v", ""},
+		{"1!x", ComponentRelease},
+		{"1.0-", ComponentRelease},
+		{"1.0 x", ComponentRelease},
+		{"1.0rcX", ComponentPre},
+		{"1.0a1-", ComponentPre},
+		{"1.0.post1.post2", ComponentPost},
+		{"1.0-1x", ComponentPost},
+		{"1.0.dev1.dev2", ComponentDev},
+		{"1.0+", ComponentLocal},
+		{"1.0+a+", ComponentLocal},
+		{"1.0+a_é", ComponentLocal},
+	}
+
+	for _, c := range versionErrorCases {
+		t.Run(c.version, func(t *testing.T) {
+			_, err := ParseVersion(c.version)
+			if !errors.Is(err, ErrInvalidVersion) {
+				t.Errorf("%v should be an invalid version error", err)
+				return
+			}
+
+			var versionErr *VersionError
+			if !errors.As(err, &versionErr) {
+				t.Errorf("%v should be a *VersionError", err)
+				return
+			}
+			if versionErr.Version != c.version || versionErr.Component != c.component {
+				t.Errorf("%s(%s) != %s(%s)", versionErr.Version, versionErr.Component, c.version, c.component)
+			}
+		})
+	}
+
+	_, err := NewVersion(0, []int64{1}, nil, nil, &Stage{"post", 1}, "")
+	var versionErr *VersionError
+	if !errors.As(err, &versionErr) || !errors.Is(err, ErrInvalidVersion) || versionErr.Component != ComponentDev {
+		t.Errorf("%v should be an invalid version error of dev", err)
+	}
+	if _, err := ParseCanonical("v1.0"); !errors.Is(err, ErrNotCanonical) || errors.Is(err, ErrInvalidVersion) {
+		t.Errorf("%v should be a non canonical version error only", err)
+	}
+}
+
+func TestNameError(t *testing.T) {
+	_, err := NewPackage("Rayane_,")
+
+	var nameErr *NameError
+	if !errors.Is(err, ErrInvalidName) || !errors.As(err, &nameErr) {
+		t.Errorf("%v should be an invalid name error", err)
+		return
+	}
+	if nameErr.Name != "Rayane_," {
+		t.Errorf("%s(actual) != Rayane_,(expected)", nameErr.Name)
+	}
+}
+
+func TestFilenameError(t *testing.T) {
+	var filenameErrorCases = []struct {
+		pkg       string
+		filename  string
+		kind      error
+		component string
+	}{
+		{"redis-sniffer", "redis-sniffer_1.0.0.tgz", ErrVersionNotFound, ComponentVersion},
+		{"turboflot", "python-turboflot-0.0.9-1.fc8.src.rpm", ErrVersionNotFound, ComponentVersion},
+		{"fiximports", "fiximport-0.1.15-py2.py3-none-any.whl", ErrNameMismatch, ComponentName},
+		{"fiximports", "fiximports-0.1.15-py2.py3-none.whl", ErrInvalidFilename, ""},
+		{"fiximports", "fiximports-0.1.15-x1-py2.py3-none-any.whl", ErrInvalidFilename, ComponentBuild},
+		{"fiximports", "fiximports-0.1.15-py2.py3-none-.whl", ErrInvalidFilename, ComponentPlatform},
+		{"fiximports", "fiximports-0.1.15.rar", ErrUnsupportedExt, ComponentExtension},
+	}
+
+	for _, c := range filenameErrorCases {
+		t.Run(c.filename, func(t *testing.T) {
+			pkg, err := NewPackage(c.pkg)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			_, err = pkg.EvaluateVersion(c.filename)
+			if !errors.Is(err, c.kind) {
+				t.Errorf("%v should be an error of %v", err, c.kind)
+				return
+			}
+
+			var filenameErr *FilenameError
+			if !errors.As(err, &filenameErr) {
+				t.Errorf("%v should be a *FilenameError", err)
+				return
+			}
+			if filenameErr.Filename != c.filename || filenameErr.Component != c.component {
+				t.Errorf("%s(%s) != %s(%s)", filenameErr.Filename, filenameErr.Component, c.filename, c.component)
+			}
+		})
+	}
+
+	if _, err := NewWheel("fiximports-0.1.15.tar.gz"); !errors.Is(err, ErrInvalidFilename) {
+		t.Errorf("%v should be an invalid filename error", err)
+	}
+}
+
+func TestRequirementError(t *testing.T) {
+	_, err := ParseRequirement("name[bar")
+
+	expected := "requirement 'name[bar' (position 8): invalid requirement, expected comma or closing bracket"
+	if err == nil || err.Error() != expected {
+		t.Errorf("%v(actual) != %s(expected)", err, expected)
+	}
+}
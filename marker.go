@@ -0,0 +1,391 @@
+package version
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Environment is the values of marker variables of a target environment, for details:
+// https://peps.python.org/pep-0508/#environment-markers.
+type Environment struct {
+	ImplementationName           string `json:"implementation_name"`
+	ImplementationVersion        string `json:"implementation_version"`
+	OSName                       string `json:"os_name"`
+	PlatformMachine              string `json:"platform_machine"`
+	PlatformRelease              string `json:"platform_release"`
+	PlatformSystem               string `json:"platform_system"`
+	PlatformVersion              string `json:"platform_version"`
+	PythonFullVersion            string `json:"python_full_version"`
+	PlatformPythonImplementation string `json:"platform_python_implementation"`
+	PythonVersion                string `json:"python_version"`
+	SysPlatform                  string `json:"sys_platform"`
+	// Extra is the extra being installed, it's only meaningful in the markers of a distribution's
+	// dependencies.
+	Extra string `json:"extra"`
+}
+
+// lookup returns the value of a marker variable.
+func (e *Environment) lookup(variable string) string {
+	switch variable {
+	case "implementation_name":
+		return e.ImplementationName
+	case "implementation_version":
+		return e.ImplementationVersion
+	case "os_name":
+		return e.OSName
+	case "platform_machine":
+		return e.PlatformMachine
+	case "platform_release":
+		return e.PlatformRelease
+	case "platform_system":
+		return e.PlatformSystem
+	case "platform_version":
+		return e.PlatformVersion
+	case "python_full_version":
+		return e.PythonFullVersion
+	case "platform_python_implementation":
+		return e.PlatformPythonImplementation
+	case "python_version":
+		return e.PythonVersion
+	case "sys_platform":
+		return e.SysPlatform
+	case "extra":
+		return e.Extra
+	default:
+		return ""
+	}
+}
+
+// markerVariables maps the variable names of pep-508 including legacy aliases to the canonical ones.
+var markerVariables = map[string]string{
+	"implementation_name":            "implementation_name",
+	"implementation_version":         "implementation_version",
+	"os_name":                        "os_name",
+	"os.name":                        "os_name",
+	"platform_machine":               "platform_machine",
+	"platform.machine":               "platform_machine",
+	"platform_release":               "platform_release",
+	"platform_system":                "platform_system",
+	"platform_version":               "platform_version",
+	"platform.version":               "platform_version",
+	"python_full_version":            "python_full_version",
+	"platform_python_implementation": "platform_python_implementation",
+	"platform.python_implementation": "platform_python_implementation",
+	"python_implementation":          "platform_python_implementation",
+	"python_version":                 "python_version",
+	"sys_platform":                   "sys_platform",
+	"sys.platform":                   "sys_platform",
+	"extra":                          "extra",
+}
+
+// marker operators, the comparison operators are the same as specifiers.
+const (
+	markerAnd   = "and"
+	markerOr    = "or"
+	markerIn    = "in"
+	markerNotIn = "not in"
+)
+
+var markerComparisons = []string{OpArbitrary, OpEqual, OpCompatible, OpNotEqual, OpLessEqual, OpGreaterEqual, OpLess, OpGreater}
+
+// Marker is an environment marker such as 'python_version < "3.8" and sys_platform == "linux"',
+// which is a sequence of comparisons and parenthesized markers joined by 'and' and 'or'.
+type Marker struct {
+	items []*markerItem
+	// ops[i] is the boolean operator between items[i] and items[i+1].
+	ops []string
+}
+
+// markerItem is either a comparison or a parenthesized marker.
+type markerItem struct {
+	group *Marker
+
+	lhs, rhs markerValue
+	op       string
+}
+
+// markerValue is either a variable or a quoted string.
+type markerValue struct {
+	variable bool
+	value    string
+}
+
+// ParseMarker parses an environment marker following the grammar of packaging, see
+// https://github.com/pypa/packaging/blob/23.0/src/packaging/_parser.py. A *MarkerError is returned
+// with the position where parsing fails.
+func ParseMarker(marker string) (*Marker, error) {
+	p := &markerParser{s: marker}
+	p.spaces()
+	m, err := p.marker()
+	if err != nil {
+		return nil, err
+	}
+	p.spaces()
+	if p.i != len(p.s) {
+		return nil, p.fail(p.i, "expected end, 'and' or 'or'")
+	}
+
+	return m, nil
+}
+
+// String returns the marker normalized as packaging does: variables are written with canonical
+// names, strings are quoted with double quotes, and redundant parentheses are removed.
+func (m *Marker) String() string {
+	return m.format(true)
+}
+
+func (m *Marker) format(top bool) string {
+	if len(m.items) == 1 {
+		return m.items[0].String()
+	}
+
+	var b strings.Builder
+	for i, item := range m.items {
+		if i > 0 {
+			b.WriteString(" " + m.ops[i-1] + " ")
+		}
+		b.WriteString(item.String())
+	}
+	if top {
+		return b.String()
+	}
+	return "(" + b.String() + ")"
+}
+
+func (item *markerItem) String() string {
+	if item.group != nil {
+		return item.group.format(false)
+	}
+	return item.lhs.String() + " " + item.op + " " + item.rhs.String()
+}
+
+func (v markerValue) String() string {
+	if v.variable {
+		return v.value
+	}
+	if strings.Contains(v.value, `"`) {
+		return "'" + v.value + "'"
+	}
+	return `"` + v.value + `"`
+}
+
+// Evaluate evaluates the marker in env, 'and' takes precedence over 'or'. Comparisons are evaluated
+// as version specifiers if the right side makes a valid specifier with the operator, otherwise as
+// strings, and an error of ErrUndefinedComparison is returned if the operator doesn't apply to
+// strings, e.g. 'os_name ~= "posix"'.
+func (m *Marker) Evaluate(env Environment) (bool, error) {
+	return m.evaluate(&env)
+}
+
+func (m *Marker) evaluate(env *Environment) (bool, error) {
+	// groups of items joined by 'and' are joined by 'or', like packaging all the items are evaluated
+	result, all := false, true
+	for i, item := range m.items {
+		if i > 0 && m.ops[i-1] == markerOr {
+			result, all = result || all, true
+		}
+		ok, err := item.evaluate(env)
+		if err != nil {
+			return false, err
+		}
+		all = all && ok
+	}
+
+	return result || all, nil
+}
+
+func (item *markerItem) evaluate(env *Environment) (bool, error) {
+	if item.group != nil {
+		return item.group.evaluate(env)
+	}
+
+	lhs, rhs := item.lhs.value, item.rhs.value
+	if item.lhs.variable {
+		lhs = env.lookup(lhs)
+	}
+	if item.rhs.variable {
+		rhs = env.lookup(rhs)
+	}
+
+	// extras are compared by the canonical names, see https://peps.python.org/pep-0685/
+	if (item.lhs.variable && item.lhs.value == "extra") || (item.rhs.variable && item.rhs.value == "extra") {
+		lhs, rhs = CanonicalizeExtra(lhs), CanonicalizeExtra(rhs)
+	}
+
+	if spec, err := ParseSpecifier(item.op + rhs); err == nil {
+		if v, err := Parse(lhs); err == nil {
+			return spec.Contains(v), nil
+		}
+	}
+
+	switch item.op {
+	case markerIn:
+		return strings.Contains(rhs, lhs), nil
+	case markerNotIn:
+		return !strings.Contains(rhs, lhs), nil
+	case OpEqual:
+		return lhs == rhs, nil
+	case OpNotEqual:
+		return lhs != rhs, nil
+	case OpLess:
+		return lhs < rhs, nil
+	case OpLessEqual:
+		return lhs <= rhs, nil
+	case OpGreater:
+		return lhs > rhs, nil
+	case OpGreaterEqual:
+		return lhs >= rhs, nil
+	default:
+		return false, fmt.Errorf("comparison '%s': %w", item, ErrUndefinedComparison)
+	}
+}
+
+// markerParser is a recursive-descent parser of markers, positions in errors are byte offsets of the
+// input.
+type markerParser struct {
+	s string
+	i int
+}
+
+// marker = marker_atom (WS? BOOLOP WS? marker_atom)*
+func (p *markerParser) marker() (*Marker, error) {
+	m := new(Marker)
+	for {
+		item, err := p.atom()
+		if err != nil {
+			return nil, err
+		}
+		m.items = append(m.items, item)
+
+		start := p.i
+		p.spaces()
+		op := p.keyword(markerAnd, markerOr)
+		if op == "" {
+			p.i = start
+			return m, nil
+		}
+		m.ops = append(m.ops, op)
+		p.spaces()
+	}
+}
+
+// marker_atom = LEFT_PARENTHESIS WS? marker WS? RIGHT_PARENTHESIS | marker_var WS? marker_op WS? marker_var
+func (p *markerParser) atom() (*markerItem, error) {
+	if p.peek() == '(' {
+		p.i++
+		p.spaces()
+		group, err := p.marker()
+		if err != nil {
+			return nil, err
+		}
+		p.spaces()
+		if p.peek() != ')' {
+			return nil, p.fail(p.i, "expected closing parenthesis")
+		}
+		p.i++
+		return &markerItem{group: group}, nil
+	}
+
+	lhs, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	p.spaces()
+	op, err := p.operator()
+	if err != nil {
+		return nil, err
+	}
+	p.spaces()
+	rhs, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+
+	return &markerItem{lhs: lhs, op: op, rhs: rhs}, nil
+}
+
+// marker_var = VARIABLE | QUOTED_STRING
+func (p *markerParser) value() (markerValue, error) {
+	start := p.i
+	if quote := p.peek(); quote == '\'' || quote == '"' {
+		end := strings.IndexByte(p.s[p.i+1:], quote)
+		if end < 0 {
+			return markerValue{}, p.fail(start, "unterminated string")
+		}
+		p.i += end + 2
+		return markerValue{value: p.s[start+1 : p.i-1]}, nil
+	}
+
+	for isAlnum(p.peek()) || p.peek() == '_' || p.peek() == '.' {
+		p.i++
+	}
+	if variable, ok := markerVariables[p.s[start:p.i]]; ok {
+		return markerValue{variable: true, value: variable}, nil
+	}
+	p.i = start
+
+	return markerValue{}, p.fail(start, "expected a marker variable or quoted string")
+}
+
+// marker_op = IN | NOT IN | OP
+func (p *markerParser) operator() (string, error) {
+	if op := p.keyword(markerIn); op != "" {
+		return op, nil
+	}
+
+	start := p.i
+	if p.keyword("not") != "" {
+		p.spaces()
+		if p.i > start+len("not") && p.keyword(markerIn) != "" {
+			return markerNotIn, nil
+		}
+		return "", p.fail(p.i, "expected 'in' after 'not'")
+	}
+
+	for _, op := range markerComparisons {
+		if strings.HasPrefix(p.s[p.i:], op) {
+			p.i += len(op)
+			return op, nil
+		}
+	}
+
+	return "", p.fail(start, "expected marker operator, one of <=, <, !=, ==, >=, >, ~=, ===, in, not in")
+}
+
+// keyword consumes one of the keywords which is not followed by a letter, digit, '_' or '.'.
+func (p *markerParser) keyword(keywords ...string) string {
+	for _, keyword := range keywords {
+		if !strings.HasPrefix(p.s[p.i:], keyword) {
+			continue
+		}
+		if next := p.i + len(keyword); next < len(p.s) && (isAlnum(p.s[next]) || p.s[next] == '_' || p.s[next] == '.') {
+			continue
+		}
+		p.i += len(keyword)
+		return keyword
+	}
+
+	return ""
+}
+
+func (p *markerParser) peek() byte {
+	if p.i < len(p.s) {
+		return p.s[p.i]
+	}
+	return 0
+}
+
+func (p *markerParser) spaces() {
+	for p.i < len(p.s) && isRequirementSpace(p.s[p.i]) {
+		p.i++
+	}
+}
+
+func (p *markerParser) fail(pos int, format string, args ...interface{}) error {
+	return &MarkerError{
+		Marker: p.s,
+		Pos:    pos,
+		Kind:   ErrInvalidMarker,
+		Err:    fmt.Errorf(format, args...),
+	}
+}
@@ -0,0 +1,152 @@
+package version
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseMarker(t *testing.T) {
+	var markerCases = []struct {
+		marker   string
+		expected string
+	}{
+		{`python_version>'3.8'`, `python_version > "3.8"`},
+		{` os.name == "nt" `, `os_name == "nt"`},
+		{`python_implementation=="CPython"`, `platform_python_implementation == "CPython"`},
+		{`'linux' in sys_platform`, `"linux" in sys_platform`},
+		{`sys_platform not  in 'linux darwin'`, `sys_platform not in "linux darwin"`},
+		{`(os_name == "nt")`, `os_name == "nt"`},
+		{`((os_name == "nt")) and extra == "a"`, `os_name == "nt" and extra == "a"`},
+		{`os_name=="a" and (os_name=="b" or os_name=="c")`, `os_name == "a" and (os_name == "b" or os_name == "c")`},
+		{`os_name=="a" and os_name=="b" or os_name=="c"`, `os_name == "a" and os_name == "b" or os_name == "c"`},
+		{`os_name == 'say "hi"'`, `os_name == 'say "hi"'`},
+	}
+
+	for _, c := range markerCases {
+		t.Run(c.marker, func(t *testing.T) {
+			m, err := ParseMarker(c.marker)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if m.String() != c.expected {
+				t.Errorf("%s(actual) != %s(expected)", m, c.expected)
+			}
+		})
+	}
+}
+
+func TestParseMarkerError(t *testing.T) {
+	var errorCases = []struct {
+		marker string
+		pos    int
+	}{
+		{``, 0},
+		{`os_name`, 7},
+		{`os_name = "a"`, 8},
+		{`os_names == "a"`, 0},
+		{`os_name == "a`, 11},
+		{`os_name notin "a"`, 8},
+		{`os_name == "a" and`, 18},
+		{`os_name == "a" nor os_name == "b"`, 15},
+		{`(os_name == "a"`, 15},
+		{`os_name == "a")`, 14},
+		{`os_name == "a" andextra == "b"`, 15},
+	}
+
+	for _, c := range errorCases {
+		t.Run(c.marker, func(t *testing.T) {
+			_, err := ParseMarker(c.marker)
+
+			var markerErr *MarkerError
+			if !errors.Is(err, ErrInvalidMarker) || !errors.As(err, &markerErr) {
+				t.Errorf("%v should be an invalid marker error", err)
+				return
+			}
+			if markerErr.Pos != c.pos {
+				t.Errorf("%d(actual) != %d(expected), %v", markerErr.Pos, c.pos, err)
+			}
+		})
+	}
+}
+
+func TestMarkerEvaluate(t *testing.T) {
+	env := Environment{
+		ImplementationName:           "cpython",
+		ImplementationVersion:        "3.12.1",
+		OSName:                       "posix",
+		PlatformMachine:              "x86_64",
+		PlatformRelease:              "6.1.0-13-amd64",
+		PlatformSystem:               "Linux",
+		PlatformVersion:              "#1 SMP PREEMPT_DYNAMIC Debian 6.1.55-1",
+		PythonFullVersion:            "3.12.1",
+		PlatformPythonImplementation: "CPython",
+		PythonVersion:                "3.12",
+		SysPlatform:                  "linux",
+	}
+
+	var evaluateCases = []struct {
+		marker   string
+		expected bool
+	}{
+		{`python_version >= "3.8"`, true},
+		{`python_version < "3.9"`, false},
+		{`python_version > "3.8"`, true},
+		{`python_version == "3.12.*"`, true},
+		{`python_full_version ~= "3.12.0"`, true},
+		{`"3.13" > python_version`, true},
+		{`python_version === "3.12"`, true},
+		{`python_version != "3.12.0"`, false},
+		{`platform_release >= "6"`, false},
+		{`sys_platform == "linux"`, true},
+		{`sys_platform == "Linux"`, false},
+		{`'linux' in sys_platform`, true},
+		{`'lin' in sys_platform`, true},
+		{`sys_platform not in "win32 cygwin"`, true},
+		{`platform_machine < "y"`, true},
+		{`platform_machine >= "y"`, false},
+		{`implementation_name == "pypy" or os_name == "posix"`, true},
+		{`implementation_name == "pypy" and os_name == "posix"`, false},
+		{`os_name == "posix" or os_name == "nt" and sys_platform == "win32"`, true},
+		{`(os_name == "posix" or os_name == "nt") and sys_platform == "win32"`, false},
+		{`extra == "test"`, false},
+		{`extra == ""`, true},
+	}
+
+	for _, c := range evaluateCases {
+		t.Run(c.marker, func(t *testing.T) {
+			m, err := ParseMarker(c.marker)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			actual, err := m.Evaluate(env)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if actual != c.expected {
+				t.Errorf("%v(actual) != %v(expected)", actual, c.expected)
+			}
+		})
+	}
+
+	m, _ := ParseMarker(`os_name ~= "posix"`)
+	if _, err := m.Evaluate(env); !errors.Is(err, ErrUndefinedComparison) {
+		t.Errorf("%v should be an undefined comparison error", err)
+	}
+}
+
+func TestRequirementMarker(t *testing.T) {
+	r, err := ParseRequirement(`pywin32>=1.0; sys_platform == "win32"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, _ := r.Marker.Evaluate(Environment{SysPlatform: "linux"}); ok {
+		t.Errorf("%s should not be satisfied on linux", r)
+	}
+	if ok, _ := r.Marker.Evaluate(Environment{SysPlatform: "win32"}); !ok {
+		t.Errorf("%s should be satisfied on win32", r)
+	}
+}
@@ -0,0 +1,105 @@
+package version
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// number is a non-negative integer of arbitrary size, pep-440 doesn't limit the size of epoch,
+// release and stage numbers, e.g. a date stamped release like 20230101123045123456789 is valid.
+// It's kept as its decimal digits without leading zeros, and zero is '0'.
+type number string
+
+// parseNumber creates a number from a string of decimal digits, which may have leading zeros.
+func parseNumber(digits string) number {
+	digits = strings.TrimLeft(digits, "0")
+	if digits == "" {
+		return "0"
+	}
+	return number(digits)
+}
+
+func newNumber(n int64) number {
+	return number(strconv.FormatInt(n, 10))
+}
+
+func (n number) isZero() bool {
+	return strings.Trim(string(n), "0") == ""
+}
+
+func (n number) compare(other number) int {
+	return compareDigits(string(n), string(other))
+}
+
+// inc returns the number plus one.
+func (n number) inc() number {
+	digits := []byte(strings.TrimLeft(string(n), "0"))
+	for i := len(digits) - 1; i >= 0; i-- {
+		if digits[i] < '9' {
+			digits[i]++
+			return number(digits)
+		}
+		digits[i] = '0'
+	}
+
+	return number("1" + string(digits))
+}
+
+// dec returns the number minus one, the number must be positive.
+func (n number) dec() number {
+	digits := []byte(string(n))
+	for i := len(digits) - 1; i >= 0; i-- {
+		if digits[i] > '0' {
+			digits[i]--
+			break
+		}
+		digits[i] = '9'
+	}
+
+	return parseNumber(string(digits))
+}
+
+// int64 returns the number as an int64, or math.MaxInt64 if it overflows.
+func (n number) int64() int64 {
+	i, err := strconv.ParseInt(string(n), 10, 64)
+	if err != nil {
+		return math.MaxInt64
+	}
+	return i
+}
+
+func (n number) big() *big.Int {
+	i, _ := new(big.Int).SetString(string(parseNumber(string(n))), 10)
+	return i
+}
+
+// segment is a pre, post or dev segment of a Version, Stage is its exported form.
+type segment struct {
+	name   string
+	number number
+}
+
+func (s *segment) String() string {
+	return s.name + string(s.number)
+}
+
+// stage returns the segment as a new Stage, or nil if there is no segment.
+func (s *segment) stage() *Stage {
+	if s == nil {
+		return nil
+	}
+
+	return &Stage{
+		Name:   s.name,
+		Number: s.number.int64(),
+	}
+}
+
+func (s *segment) big() *big.Int {
+	if s == nil {
+		return nil
+	}
+	return s.number.big()
+}
@@ -1,6 +1,7 @@
 package version
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -172,3 +173,122 @@ func TestEvaluateVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestEvaluateCanonicalVersion(t *testing.T) {
+	var packageCases = []struct {
+		pkg      string
+		filename string
+		expected string
+	}{
+		{"embo", "embo-0.4.0-py3-none-any.whl", "0.4"},
+		{"embo", "embo-0.4.tar.gz", "0.4"},
+		{"embo", "embo-0.4.0.post1.zip", "0.4.post1"},
+		{"embo", "embo-1.0.0.win32.exe", "1"},
+		{"embo", "embo-0.4.1.tar.gz", "0.4.1"},
+	}
+
+	for _, c := range packageCases {
+		t.Run(c.filename, func(t *testing.T) {
+			pkg, err := NewPackage(c.pkg)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			version, err := pkg.EvaluateCanonicalVersion(c.filename)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if version != c.expected {
+				t.Errorf("%s(actual) != %s(expected)", version, c.expected)
+			}
+		})
+	}
+}
+
+func TestPackageExtras(t *testing.T) {
+	if extra := CanonicalizeExtra("Socks_Proxy..v2"); extra != "socks-proxy-v2" {
+		t.Errorf("%s(actual) != socks-proxy-v2(expected)", extra)
+	}
+
+	pkg, _ := NewPackage("Requests")
+	withExtras, err := pkg.WithExtras("Socks", "security", "socks", "use_chardet_on_py3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if withExtras.String() != "Package<requests[security,socks,use-chardet-on-py3]>" || len(pkg.Extras()) != 0 {
+		t.Errorf("%s(actual) != Package<requests[security,socks,use-chardet-on-py3]>(expected)", withExtras)
+	}
+	if _, err := pkg.WithExtras("bad extra"); err == nil {
+		t.Errorf("'bad extra' should be an invalid extra")
+	}
+
+	r, _ := ParseRequirement("Requests[Socks, security] >= 2")
+	if p, err := r.Package(); err != nil || p.String() != "Package<requests[security,socks]>" {
+		t.Errorf("%v(actual) != Package<requests[security,socks]>(expected), %v", p, err)
+	}
+}
+
+func TestActiveRequirements(t *testing.T) {
+	var requiresDist []*Requirement
+	for _, line := range []string{
+		"charset-normalizer<4,>=2",
+		`PySocks!=1.5.7,>=1.5.6; extra == "socks"`,
+		`chardet<6,>=3.0.2; extra == "use-chardet-on-py3"`,
+		`win-inet-pton; (sys_platform == "win32" and python_version == "2.7") and extra == "socks"`,
+		`importlib-metadata; python_version < "3.8"`,
+	} {
+		r, err := ParseRequirement(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		requiresDist = append(requiresDist, r)
+	}
+
+	env := Environment{PythonVersion: "3.12", SysPlatform: "linux"}
+	var activeCases = []struct {
+		extras   []string
+		expected string
+	}{
+		{nil, "charset-normalizer"},
+		{[]string{"socks"}, "charset-normalizer PySocks"},
+		{[]string{"Use_Chardet_On_Py3", "SOCKS"}, "charset-normalizer PySocks chardet"},
+		{[]string{"unknown"}, "charset-normalizer"},
+	}
+
+	pkg, _ := NewPackage("requests")
+	for _, c := range activeCases {
+		t.Run(strings.Join(c.extras, ","), func(t *testing.T) {
+			p, _ := pkg.WithExtras(c.extras...)
+			active, err := p.ActiveRequirements(requiresDist, env)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			var names []string
+			for _, r := range active {
+				names = append(names, r.Name)
+			}
+			if strings.Join(names, " ") != c.expected {
+				t.Errorf("%v(actual) != %s(expected)", names, c.expected)
+			}
+		})
+	}
+
+	if ok, _ := requiresDist[2].IsActive(env, "use.chardet.on.py3"); !ok {
+		t.Errorf("%s should be active with extra 'use.chardet.on.py3'", requiresDist[2])
+	}
+	// an empty extra is only evaluated if no extras are requested
+	negated, _ := ParseRequirement(`tomli; extra != "test"`)
+	if ok, _ := negated.IsActive(env, "test"); ok {
+		t.Errorf("%s should not be active with extra 'test'", negated)
+	}
+	if ok, _ := negated.IsActive(env); !ok {
+		t.Errorf("%s should be active without extras", negated)
+	}
+	if ok, _ := negated.IsActive(env, "test", "docs"); !ok {
+		t.Errorf("%s should be active with extra 'docs'", negated)
+	}
+}
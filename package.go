@@ -3,12 +3,16 @@ package version
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 	"unicode"
 )
 
 type Package struct {
 	name string
+	// extras is the canonical names of requested extras, sorted without duplicates.
+	extras []string
+	cache  *VersionCache
 }
 
 // CanonicalizePackage standardizes a package name, for detail:
@@ -19,6 +23,12 @@ func CanonicalizePackage(name string) string {
 	return strings.ToLower(irregularPackageLetters.ReplaceAllString(name, "-"))
 }
 
+// CanonicalizeExtra standardizes an extra name with the same rules as package names, for detail:
+// https://peps.python.org/pep-0685/#specification
+func CanonicalizeExtra(name string) string {
+	return CanonicalizePackage(name)
+}
+
 var irregularLegacyPackageLetters = regexp.MustCompile(`[^0-9a-zA-Z]+`)
 
 func CanonicalizeLegacyPackage(name string) string {
@@ -26,17 +36,20 @@ func CanonicalizeLegacyPackage(name string) string {
 }
 
 func NewPackage(name string) (*Package, error) {
-	name = CanonicalizePackage(name)
-	if !packageNameRe.MatchString(name) {
-		return nil, fmt.Errorf("illegal package name '%s'", name)
+	canonical := CanonicalizePackage(name)
+	if !packageNameRe.MatchString(canonical) {
+		return nil, &NameError{Name: name, Kind: ErrInvalidName}
 	}
 
 	return &Package{
-		name: name,
+		name: canonical,
 	}, nil
 }
 
 func (p *Package) String() string {
+	if len(p.extras) != 0 {
+		return fmt.Sprintf("Package<%s[%s]>", p.name, strings.Join(p.extras, ","))
+	}
 	return fmt.Sprintf("Package<%s>", p.name)
 }
 
@@ -44,9 +57,75 @@ func (p *Package) Name() string {
 	return p.name
 }
 
+// Extras returns the canonical names of requested extras in order.
+func (p *Package) Extras() []string {
+	return append([]string(nil), p.extras...)
+}
+
+// WithExtras returns a copy of p requesting the extras, which are canonicalized by CanonicalizeExtra.
+func (p *Package) WithExtras(extras ...string) (*Package, error) {
+	seen := make(map[string]bool)
+	var canonical []string
+	for _, extra := range extras {
+		name := CanonicalizeExtra(extra)
+		if !packageNameRe.MatchString(name) {
+			return nil, &NameError{Name: extra, Kind: ErrInvalidName}
+		}
+		if !seen[name] {
+			seen[name] = true
+			canonical = append(canonical, name)
+		}
+	}
+	sort.Strings(canonical)
+
+	return &Package{
+		name:   p.name,
+		extras: canonical,
+		cache:  p.cache,
+	}, nil
+}
+
+// WithCache returns a copy of p which parses versions and canonicalizes names through cache, so that
+// evaluating many filenames doesn't reparse identical versions. A nil cache disables caching.
+func (p *Package) WithCache(cache *VersionCache) *Package {
+	return &Package{
+		name:   p.name,
+		extras: p.extras,
+		cache:  cache,
+	}
+}
+
+// ActiveRequirements returns the dependencies among requiresDist, such as the Requires-Dist entries
+// of metadata, which apply in env when p is installed with its extras, see Requirement.IsActive.
+func (p *Package) ActiveRequirements(requiresDist []*Requirement, env Environment) ([]*Requirement, error) {
+	var active []*Requirement
+	for _, r := range requiresDist {
+		ok, err := r.IsActive(env, p.extras...)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			active = append(active, r)
+		}
+	}
+
+	return active, nil
+}
+
 // EvaluateVersion extracts version from filename of current package, original implementations can
 // refer to https://github.com/pypa/pip/blob/23.0.1/src/pip/_internal/index/package_finder.py#L108.
 func (p *Package) EvaluateVersion(filename string) (string, error) {
+	return p.evaluateVersion(filename, false)
+}
+
+// EvaluateCanonicalVersion is the same as EvaluateVersion but returns the version in canonical form
+// with trailing zeros stripped, so that filenames of equal versions such as 1.0 and 1.0.0 have the
+// same result, see Canonical.
+func (p *Package) EvaluateCanonicalVersion(filename string) (string, error) {
+	return p.evaluateVersion(filename, true)
+}
+
+func (p *Package) evaluateVersion(filename string, canonical bool) (string, error) {
 	fragment, ext := splitFilename(filename)
 
 	if ext = strings.ToLower(ext); StandardExt.Contains(ext) { // keep the same as pip
@@ -56,15 +135,20 @@ func (p *Package) EvaluateVersion(filename string) (string, error) {
 			if err != nil {
 				return "", err
 			}
-			if CanonicalizePackage(whl.Name) != p.name {
-				return "", fmt.Errorf("package name '%s' doesn't match that in filename '%s'", p.name, filename)
+			if p.cache.CanonicalizePackage(whl.Name) != p.name {
+				return "", &FilenameError{
+					Filename:  filename,
+					Component: ComponentName,
+					Kind:      ErrNameMismatch,
+					Err:       fmt.Errorf("package name '%s' doesn't match '%s'", p.name, whl.Name),
+				}
 			}
 			version = whl.Version
 		}
 
 		if version == "" {
 			if version = p.extractVersionFromFragment(fragment); version == "" {
-				return "", fmt.Errorf("version not found in '%s'", filename)
+				return "", &FilenameError{Filename: filename, Component: ComponentVersion, Kind: ErrVersionNotFound}
 			}
 		}
 
@@ -72,27 +156,38 @@ func (p *Package) EvaluateVersion(filename string) (string, error) {
 			version = version[:scope[0]]
 		}
 
-		v, err := Parse(version)
+		v, err := p.cache.Parse(version)
 		if err != nil {
-			return "", fmt.Errorf("illegal version '%s'", version)
+			return "", &FilenameError{Filename: filename, Component: ComponentVersion, Kind: ErrInvalidVersion, Err: err}
 		}
 
+		if canonical {
+			return Canonical(v), nil
+		}
 		return v.Complete(), nil // return detailed version
 	} else if LegacyExt.Contains(ext) { // rules are casual and used to extract as many versions as possible from filenames
 		version := p.extractVersionFromLegacyFragment(fragment)
 
 		if version == "" {
-			return "", fmt.Errorf("version not found in '%s'", filename)
+			return "", &FilenameError{Filename: filename, Component: ComponentVersion, Kind: ErrVersionNotFound}
 		}
 
-		v, err := Parse(version)
+		v, err := p.cache.Parse(version)
 		if err != nil {
-			return "", fmt.Errorf("illegal version '%s'", version)
+			return "", &FilenameError{Filename: filename, Component: ComponentVersion, Kind: ErrInvalidVersion, Err: err}
 		}
 
+		if canonical {
+			return CanonicalizeVersion(v.Base(), true), nil
+		}
 		return v.Base(), nil // return brief version
 	} else {
-		return "", fmt.Errorf("unsupported file extension '%s'", ext)
+		return "", &FilenameError{
+			Filename:  filename,
+			Component: ComponentExtension,
+			Kind:      ErrUnsupportedExt,
+			Err:       fmt.Errorf("extension '%s'", ext),
+		}
 	}
 }
 
@@ -131,14 +226,15 @@ func (p *Package) extractVersionFromLegacyFragment(fragment string) string {
 	return ""
 }
 
+// Wheel is the parsed filename of a wheel, its json representation is stable.
 type Wheel struct {
-	Filename string
-	Name     string
-	Version  string
-	Btag     string
-	Pyvers   []string
-	Abis     []string
-	Plats    []string
+	Filename string   `json:"filename"`
+	Name     string   `json:"name"`
+	Version  string   `json:"version"`
+	Btag     string   `json:"build_tag,omitempty"`
+	Pyvers   []string `json:"python_tags"`
+	Abis     []string `json:"abi_tags"`
+	Plats    []string `json:"platform_tags"`
 }
 
 // NewWheel create a Wheel object from filename, which contains five segments of filename
@@ -147,7 +243,7 @@ type Wheel struct {
 func NewWheel(filename string) (*Wheel, error) {
 	match := wheelFilenameRe.FindStringSubmatch(filename)
 	if match == nil {
-		return nil, fmt.Errorf("illegal wheel filename '%s'", filename)
+		return nil, &FilenameError{Filename: filename, Component: wheelFilenameComponent(filename), Kind: ErrInvalidFilename}
 	}
 
 	return &Wheel{
@@ -160,3 +256,30 @@ func NewWheel(filename string) (*Wheel, error) {
 		Plats:    strings.Split(match[wheelFilenameRe.SubexpIndex("plat")], "."),
 	}, nil
 }
+
+// wheelFilenameComponent guesses the failing segment of an illegal wheel filename.
+func wheelFilenameComponent(filename string) string {
+	fragment, ext := splitFilename(filename)
+	if !strings.EqualFold(ext, ExtWhl) {
+		return ComponentExtension
+	}
+
+	segments := strings.Split(fragment, "-")
+	if len(segments) != 5 && len(segments) != 6 {
+		return ""
+	}
+	components := []string{ComponentName, ComponentVersion, ComponentPython, ComponentABI, ComponentPlatform}
+	if len(segments) == 6 {
+		components = []string{ComponentName, ComponentVersion, ComponentBuild, ComponentPython, ComponentABI, ComponentPlatform}
+	}
+	for i, segment := range segments {
+		if segment == "" || strings.IndexFunc(segment, unicode.IsSpace) >= 0 {
+			return components[i]
+		}
+		if components[i] == ComponentBuild && (segment[0] < '0' || segment[0] > '9') {
+			return ComponentBuild
+		}
+	}
+
+	return ""
+}
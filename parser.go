@@ -0,0 +1,187 @@
+package version
+
+// versionParser is a hand-written parser accepting exactly the versions matched by irregularVersionRe,
+// it walks through the input once without any backtracking since every optional separator it takes
+// greedily is also optional in the following segments, which is the same choice the regexp makes.
+type versionParser struct {
+	s string
+	i int
+}
+
+var (
+	// candidates of stage names, the longer ones come first when they share a prefix
+	preLetters  = []string{"preview", "alpha", "beta", "pre", "rc", "a", "b", "c"}
+	postLetters = []string{"post", "rev", "r"}
+	devLetters  = []string{"dev"}
+)
+
+// parseVersion parses a version as parseVersionRegexp does, it reports false if the version is invalid
+// together with the component where the parser stops, which is empty if no release is found.
+func parseVersion(version string) (*Version, string, bool) {
+	for i := 0; i < len(version); i++ {
+		if version[i] >= 0x80 {
+			// case folding of the regexp matches some non-ascii letters, such as 'ſ' for 's', the
+			// parser below never accepts a non-ascii letter, it's only used to locate the error
+			if v, err := parseVersionRegexp(version); err == nil {
+				return v, "", true
+			}
+			break
+		}
+	}
+
+	p := &versionParser{s: version}
+	p.spaces()
+	if p.peek() == 'v' || p.peek() == 'V' {
+		p.i++
+	}
+
+	v := &Version{epoch: "0"}
+	digits := p.digits()
+	if digits == "" {
+		return nil, "", false
+	}
+	if p.peek() == '!' {
+		p.i++
+		v.epoch = parseNumber(digits)
+		if digits = p.digits(); digits == "" {
+			return nil, ComponentRelease, false
+		}
+	}
+	component := ComponentRelease
+	v.release = make([]number, 1, 4)
+	v.release[0] = parseNumber(digits)
+	for p.peek() == '.' && isDigit(p.peekAt(1)) {
+		p.i++
+		v.release = append(v.release, parseNumber(p.digits()))
+	}
+
+	if v.pre = p.stage(preLetters); v.pre != nil {
+		component = ComponentPre
+	}
+	if p.peek() == '-' && isDigit(p.peekAt(1)) {
+		// this is using the implicit post release syntax (e.g. 1.0-1)
+		p.i++
+		v.post = &segment{name: "post", number: parseNumber(p.digits())}
+	} else {
+		v.post = p.stage(postLetters)
+	}
+	if v.post != nil {
+		component = ComponentPost
+	}
+	if v.dev = p.stage(devLetters); v.dev != nil {
+		component = ComponentDev
+	}
+
+	if p.peek() == '+' {
+		p.i++
+		component = ComponentLocal
+		start := p.i
+		if !p.alnums() {
+			return nil, component, false
+		}
+		for isSeparator(p.peek()) && isAlnum(p.peekAt(1)) {
+			p.i++
+			p.alnums()
+		}
+		v.local = parseLocalVersion(p.s[start:p.i])
+	}
+
+	p.spaces()
+	if p.i != len(p.s) {
+		return nil, component, false
+	}
+
+	return v, "", true
+}
+
+func (p *versionParser) peek() byte {
+	return p.peekAt(0)
+}
+
+// peekAt returns the byte at offset from the current position, or 0 at the end of input.
+func (p *versionParser) peekAt(offset int) byte {
+	if p.i+offset < len(p.s) {
+		return p.s[p.i+offset]
+	}
+	return 0
+}
+
+// spaces skips the whitespaces matched by '\s' of regexp.
+func (p *versionParser) spaces() {
+	for {
+		switch p.peek() {
+		case ' ', '\t', '\n', '\f', '\r':
+			p.i++
+		default:
+			return
+		}
+	}
+}
+
+func (p *versionParser) digits() string {
+	start := p.i
+	for isDigit(p.peek()) {
+		p.i++
+	}
+	return p.s[start:p.i]
+}
+
+func (p *versionParser) alnums() bool {
+	start := p.i
+	for isAlnum(p.peek()) {
+		p.i++
+	}
+	return p.i > start
+}
+
+// stage parses '[-_.]?<letter>[-_.]?[0-9]*' with one of letters, nothing is consumed if there is no
+// such stage.
+func (p *versionParser) stage(letters []string) *segment {
+	start := p.i
+	if isSeparator(p.peek()) {
+		p.i++
+	}
+
+	for _, letter := range letters {
+		if !hasPrefixFold(p.s[p.i:], letter) {
+			continue
+		}
+		p.i += len(letter)
+		if isSeparator(p.peek()) {
+			p.i++
+		}
+
+		return &segment{
+			name:   canonicalLetter(letter),
+			number: parseNumber(p.digits()),
+		}
+	}
+
+	p.i = start
+	return nil
+}
+
+// hasPrefixFold reports whether s begins with prefix in lower case, ignoring the case of ascii letters.
+func hasPrefixFold(s, prefix string) bool {
+	if len(s) < len(prefix) {
+		return false
+	}
+	for i := 0; i < len(prefix); i++ {
+		if s[i]|0x20 != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isAlnum(c byte) bool {
+	return isDigit(c) || (c|0x20 >= 'a' && c|0x20 <= 'z')
+}
+
+func isSeparator(c byte) bool {
+	return c == '-' || c == '_' || c == '.'
+}
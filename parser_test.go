@@ -0,0 +1,132 @@
+package version
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var parserVersions = []string{
+	"1.0",
+	"v1.0",
+	" \t1.0.0\n",
+	"1!2.0",
+	"01!02.03",
+	"1.0a1",
+	"1.0-ALPHA-1",
+	"1.0_beta.2",
+	"1.0c",
+	"1.0pre1",
+	"1.0.preview",
+	"1.0rc1.post1.dev1",
+	"1.0-1",
+	"1.0a-1",
+	"1.0a--1",
+	"1.0a.dev",
+	"1.0rev",
+	"1.0.r3",
+	"1.0.dev-+a",
+	"1.0+ubuntu-1_A.b",
+	"1.0+",
+	"1.0+a..b",
+	"1.0-",
+	"1.0.",
+	"1.0a1b1",
+	"1.0.post1.post2",
+	"!1.0",
+	"v",
+	"",
+	"1.0 +a",
+	"1.0poſt1",
+	"1.0+ſ",
+	"20230101123045123456789",
+}
+
+func TestParserEquivalence(t *testing.T) {
+	check := func(version string) {
+		expected, expectedErr := parseVersionRegexp(version)
+		actual, _, ok := parseVersion(version)
+		if ok != (expectedErr == nil) {
+			t.Errorf("'%s': %v(actual) != %v(expected)", version, ok, expectedErr)
+			return
+		}
+		if ok && !reflect.DeepEqual(actual, expected) {
+			t.Errorf("'%s': %s(actual) != %s(expected)", version, actual.Complete(), expected.Complete())
+		}
+	}
+
+	for _, version := range parserVersions {
+		check(version)
+	}
+
+	// versions are randomly assembled from fragments to cover the corner cases of separators
+	fragments := []string{
+		"0", "1", "01", "12", ".", "-", "_", "!", "+", " ", "\t", "v", "V", "a", "b", "c", "A", "rc", "RC",
+		"alpha", "beta", "pre", "preview", "post", "Post", "rev", "r", "dev", "DEV", "x", "ubuntu", "*",
+	}
+	r := rand.New(rand.NewSource(440))
+	for i := 0; i < 100000; i++ {
+		var sb strings.Builder
+		if r.Intn(2) == 0 {
+			sb.WriteString("1")
+		}
+		for n := r.Intn(8) + 1; n > 0; n-- {
+			sb.WriteString(fragments[r.Intn(len(fragments))])
+		}
+		check(sb.String())
+	}
+}
+
+var benchmarkVersions = []string{
+	"1.0",
+	"2.31.0",
+	"v1.0.0rc1",
+	"1!2.0.post1.dev3+ubuntu.1",
+	"1.0-ALPHA-1",
+	"20230101123045123456789",
+}
+
+func BenchmarkParseVersion(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, version := range benchmarkVersions {
+			if _, err := ParseVersion(version); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkParseVersionRegexp(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, version := range benchmarkVersions {
+			if _, err := parseVersionRegexp(version); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkEvaluateVersion(b *testing.B) {
+	p, err := NewPackage("requests")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	filenames := []string{
+		"requests-2.31.0-py3-none-any.whl",
+		"requests-2.31.0.tar.gz",
+		"requests-2.0.0rc1.zip",
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, filename := range filenames {
+			if _, err := p.EvaluateVersion(filename); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
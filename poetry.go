@@ -0,0 +1,368 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ParsePoetryConstraint translates a constraint of poetry into pep-440 specifiers, for the syntax:
+// https://python-poetry.org/docs/dependency-specification/#version-constraints. It supports
+//
+//   - caret requirements, e.g. '^1.2.3' for '>=1.2.3,<2.0.0' and '^0.2' for '>=0.2,<0.3'
+//   - tilde requirements, e.g. '~1.2.3' for '>=1.2.3,<1.3.0' and '~1' for '>=1,<2'
+//   - wildcards of '*', 'x' or 'X', e.g. '1.2.*' for '>=1.2,<1.3' and '*' for any version
+//   - hyphen ranges of npm, e.g. '1.2 - 2.3.4' for '>=1.2,<=2.3.4' and '1.2 - 2.3' for '>=1.2,<2.4'
+//   - comparisons, e.g. '>=1.2', '!=1.5.*', '=1.0' and '1.0' for '==1.0'
+//
+// Constraints joined by commas or whitespaces must all be satisfied, and '||' joins alternatives.
+// Like poetry, overlapping or adjacent ranges of alternatives are merged, e.g. '^1.2 || ^2.0' for
+// '>=1.2,<3.0', other alternatives are united by SpecifierSet.Union, which fails with
+// ErrNotExpressible if the result isn't a specifier set, e.g. '<1 || >2'. Errors are *SpecifierError
+// of ErrInvalidSpecifier or ErrNotExpressible.
+func ParsePoetryConstraint(constraint string) (*SpecifierSet, error) {
+	if strings.TrimSpace(constraint) == "" {
+		return new(SpecifierSet), nil
+	}
+
+	set, err := parsePoetryConstraint(constraint)
+	if err != nil {
+		kind := ErrInvalidSpecifier
+		if errors.Is(err, ErrNotExpressible) {
+			kind = ErrNotExpressible
+		}
+		return nil, &SpecifierError{Specifier: constraint, Kind: kind, Err: err}
+	}
+
+	return set, nil
+}
+
+func parsePoetryConstraint(constraint string) (*SpecifierSet, error) {
+	var union *poetryConstraint
+	for _, alternative := range strings.Split(strings.ReplaceAll(constraint, "||", "|"), "|") {
+		c, err := parsePoetryConjunction(alternative)
+		if err != nil {
+			return nil, err
+		}
+
+		if union == nil {
+			union = c
+		} else if union, err = union.union(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return union.specifierSet()
+}
+
+// poetryConstraint is a range of versions intersected with other specifiers, the bounds of range are
+// nil if it's unbounded.
+type poetryConstraint struct {
+	min, max               *Version
+	includeMin, includeMax bool
+	specs                  []*Specifier
+}
+
+func parsePoetryConjunction(conjunction string) (*poetryConstraint, error) {
+	tokens := strings.FieldsFunc(conjunction, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty constraint")
+	}
+
+	c := new(poetryConstraint)
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		switch {
+		case i+2 < len(tokens) && tokens[i+1] == "-":
+			if err := c.hyphen(token, tokens[i+2]); err != nil {
+				return nil, err
+			}
+			i += 2
+			continue
+		case strings.Trim(token, "~=!<>^") == "":
+			// an operator separated from its version by whitespaces
+			if i+1 == len(tokens) {
+				return nil, fmt.Errorf("missing version after '%s'", token)
+			}
+			i++
+			token += tokens[i]
+		}
+
+		if err := c.single(token); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// single intersects c with a single constraint such as '^1.2' or '>=1.0'.
+func (c *poetryConstraint) single(constraint string) error {
+	if constraint == "*" || strings.EqualFold(constraint, "x") {
+		return nil
+	}
+
+	op := constraint[:len(constraint)-len(strings.TrimLeft(constraint, "~=!<>^"))]
+	version := constraint[len(op):]
+	if prefix, ok := trimWildcard(version); ok {
+		switch op {
+		case "", "=", OpEqual:
+			return c.wildcard(prefix)
+		case OpNotEqual:
+			return c.specifier(OpNotEqual + prefix + ".*")
+		default:
+			return fmt.Errorf("unexpected wildcard in '%s'", constraint)
+		}
+	}
+
+	switch op {
+	case "^":
+		return c.caret(version)
+	case "~":
+		return c.tilde(version)
+	case "", "=", OpEqual:
+		v, err := ParseVersion(version)
+		if err != nil {
+			return err
+		}
+		c.intersect(v, v, true, true)
+		return nil
+	case OpGreaterEqual, OpGreater:
+		v, err := ParseVersion(version)
+		if err != nil {
+			return err
+		}
+		c.intersect(v, nil, op == OpGreaterEqual, false)
+		return nil
+	case OpLessEqual, OpLess:
+		v, err := ParseVersion(version)
+		if err != nil {
+			return err
+		}
+		c.intersect(nil, v, false, op == OpLessEqual)
+		return nil
+	case OpCompatible, OpNotEqual, OpArbitrary:
+		return c.specifier(constraint)
+	default:
+		return fmt.Errorf("unexpected operator '%s'", op)
+	}
+}
+
+// caret allows updates which don't modify the left-most non-zero segment of the first three ones.
+func (c *poetryConstraint) caret(version string) error {
+	v, err := ParseVersion(version)
+	if err != nil {
+		return err
+	}
+
+	n := len(v.release)
+	if n > 3 {
+		n = 3
+	}
+	index := n - 1
+	for i := 0; i < n; i++ {
+		if !v.release[i].isZero() {
+			index = i
+			break
+		}
+	}
+
+	return c.bumpedRange(v, index)
+}
+
+// tilde allows updates of the patch version if the minor version is specified, otherwise updates of
+// the minor version.
+func (c *poetryConstraint) tilde(version string) error {
+	v, err := ParseVersion(version)
+	if err != nil {
+		return err
+	}
+
+	index := 1
+	if len(v.release) == 1 {
+		index = 0
+	}
+
+	return c.bumpedRange(v, index)
+}
+
+// wildcard allows the versions starting with the release prefix.
+func (c *poetryConstraint) wildcard(prefix string) error {
+	if prefix == "" {
+		return nil
+	}
+
+	v, err := ParseVersion(prefix)
+	if err != nil {
+		return err
+	}
+	if v.pre != nil || v.post != nil || v.dev != nil || len(v.local) != 0 {
+		return fmt.Errorf("unexpected wildcard after '%s'", prefix)
+	}
+
+	return c.bumpedRange(v, len(v.release)-1)
+}
+
+// hyphen allows the inclusive range, a partial upper bound allows all the versions starting with it.
+func (c *poetryConstraint) hyphen(lower, upper string) error {
+	min, err := ParseVersion(lower)
+	if err != nil {
+		return err
+	}
+	max, err := ParseVersion(upper)
+	if err != nil {
+		return err
+	}
+
+	if len(max.release) >= 3 {
+		c.intersect(min, max, true, true)
+		return nil
+	}
+	if max, err = max.BumpRelease(len(max.release) - 1); err != nil {
+		return err
+	}
+	c.intersect(min, max, true, false)
+
+	return nil
+}
+
+// bumpedRange intersects c with [v, u) where u is v with the release bumped at index.
+func (c *poetryConstraint) bumpedRange(v *Version, index int) error {
+	max, err := v.BumpRelease(index)
+	if err != nil {
+		return err
+	}
+	c.intersect(v, max, true, false)
+
+	return nil
+}
+
+func (c *poetryConstraint) specifier(spec string) error {
+	s, err := ParseSpecifier(spec)
+	if err != nil {
+		return err
+	}
+	c.specs = append(c.specs, s)
+
+	return nil
+}
+
+// intersect narrows the range of c, a nil bound is unbounded.
+func (c *poetryConstraint) intersect(min, max *Version, includeMin, includeMax bool) {
+	if min != nil {
+		if cmp := compareBound(min, c.min); c.min == nil || cmp > 0 || (cmp == 0 && !includeMin) {
+			c.min, c.includeMin = min, includeMin
+		}
+	}
+	if max != nil {
+		if cmp := compareBound(max, c.max); c.max == nil || cmp < 0 || (cmp == 0 && !includeMax) {
+			c.max, c.includeMax = max, includeMax
+		}
+	}
+}
+
+func compareBound(a, b *Version) int {
+	if a == nil || b == nil {
+		return 0
+	}
+	return a.Compare(b)
+}
+
+// union merges the ranges if they overlap or are adjacent as poetry does, otherwise unites them by
+// SpecifierSet.Union.
+func (c *poetryConstraint) union(other *poetryConstraint) (*poetryConstraint, error) {
+	if len(c.specs) == 0 && len(other.specs) == 0 && c.connected(other) {
+		merged := &poetryConstraint{}
+		if c.min != nil && other.min != nil {
+			merged.min, merged.includeMin = c.min, c.includeMin
+			if cmp := other.min.Compare(c.min); cmp < 0 || (cmp == 0 && other.includeMin) {
+				merged.min, merged.includeMin = other.min, other.includeMin
+			}
+		}
+		if c.max != nil && other.max != nil {
+			merged.max, merged.includeMax = c.max, c.includeMax
+			if cmp := other.max.Compare(c.max); cmp > 0 || (cmp == 0 && other.includeMax) {
+				merged.max, merged.includeMax = other.max, other.includeMax
+			}
+		}
+		return merged, nil
+	}
+
+	set, err := c.specifierSet()
+	if err != nil {
+		return nil, err
+	}
+	otherSet, err := other.specifierSet()
+	if err != nil {
+		return nil, err
+	}
+	union, err := set.Union(otherSet)
+	if err != nil {
+		return nil, err
+	}
+
+	return &poetryConstraint{specs: union.specs}, nil
+}
+
+// connected reports whether the ranges overlap or are adjacent, e.g. [1.2, 2.0) and [2.0, 3.0).
+func (c *poetryConstraint) connected(other *poetryConstraint) bool {
+	below := func(a, b *poetryConstraint) bool {
+		if a.max == nil || b.min == nil {
+			return false
+		}
+		cmp := a.max.Compare(b.min)
+		return cmp < 0 || (cmp == 0 && !a.includeMax && !b.includeMin)
+	}
+
+	return !below(c, other) && !below(other, c)
+}
+
+func (c *poetryConstraint) specifierSet() (*SpecifierSet, error) {
+	var specs []string
+	switch {
+	case c.min != nil && c.max != nil && c.includeMin && c.includeMax && c.min.Compare(c.max) == 0:
+		specs = append(specs, OpEqual+c.min.Complete())
+	default:
+		if c.min != nil {
+			op := OpGreater
+			if c.includeMin {
+				op = OpGreaterEqual
+			}
+			specs = append(specs, op+c.min.Complete())
+		}
+		if c.max != nil {
+			op := OpLess
+			if c.includeMax {
+				op = OpLessEqual
+			}
+			specs = append(specs, op+c.max.Complete())
+		}
+	}
+
+	set := NewSpecifierSet(c.specs...)
+	for _, spec := range specs {
+		s, err := ParseSpecifier(spec)
+		if err != nil {
+			return nil, err
+		}
+		set.specs = append(set.specs, s)
+	}
+
+	return set, nil
+}
+
+// trimWildcard returns the release prefix of a version ending with a wildcard, e.g. '1.2' for '1.2.x'.
+func trimWildcard(version string) (string, bool) {
+	for _, wildcard := range []string{"*", "x", "X"} {
+		if version == wildcard {
+			return "", true
+		}
+		if strings.HasSuffix(version, "."+wildcard) {
+			return strings.TrimSuffix(version, "."+wildcard), true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,91 @@
+package version
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParsePoetryConstraint(t *testing.T) {
+	var poetryCases = []struct {
+		constraint string
+		expected   string
+	}{
+		{"", ""},
+		{"*", ""},
+		{"^1.2.3", "<2.0.0,>=1.2.3"},
+		{"^1.2", "<2.0,>=1.2"},
+		{"^1", "<2,>=1"},
+		{"^0.2.3", "<0.3.0,>=0.2.3"},
+		{"^0.0.3", "<0.0.4,>=0.0.3"},
+		{"^0.0", "<0.1,>=0.0"},
+		{"^0", "<1,>=0"},
+		{"^1.2.3b1", "<2.0.0,>=1.2.3b1"},
+		{"~1.2.3", "<1.3.0,>=1.2.3"},
+		{"~1.2", "<1.3,>=1.2"},
+		{"~1", "<2,>=1"},
+		{"~=1.2.3", "~=1.2.3"},
+		{"1.2.*", "<1.3,>=1.2"},
+		{"1.x", "<2,>=1"},
+		{"==1.2.X", "<1.3,>=1.2"},
+		{"!=1.2.*", "!=1.2.*"},
+		{"1.2 - 2.3.4", "<=2.3.4,>=1.2"},
+		{"1.2.3 - 2.3", "<2.4,>=1.2.3"},
+		{"1.2.3 - 2", "<3,>=1.2.3"},
+		{"1.0", "==1.0"},
+		{"=1.0+local", "==1.0+local"},
+		{">= 1.2, < 1.5", "<1.5,>=1.2"},
+		{">=1.2 <1.5 !=1.3", "!=1.3,<1.5,>=1.2"},
+		{">=1.2,<1.5,>1.3,<=1.4", "<=1.4,>1.3"},
+		{"^1.2 || ^2.0", "<3.0,>=1.2"},
+		{"^1.2 | ^2.0 || ^3.0", "<4.0,>=1.2"},
+		{">=1.2,<1.5 || >=1.4", ">=1.2"},
+		{"<1.2 || >=1.2", ""},
+		{"~1.2 || ~1.2.3", "<1.3,>=1.2"},
+		{"1.0 || >=1.0,!=1.5", "!=1.5,>=1.0"},
+	}
+
+	for _, c := range poetryCases {
+		t.Run(c.constraint, func(t *testing.T) {
+			set, err := ParsePoetryConstraint(c.constraint)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if set.String() != c.expected {
+				t.Errorf("%s(actual) != %s(expected)", set, c.expected)
+			}
+		})
+	}
+}
+
+func TestInvalidPoetryConstraint(t *testing.T) {
+	var invalidConstraints = []string{
+		"^",
+		">=",
+		"^1.2 ||",
+		"1.*.3",
+		">=1.2.*",
+		"^1.0.*",
+		"french toast",
+		"^1.0+local",
+		"<1 || >2",
+		"1.0 || 2.0",
+	}
+
+	for _, constraint := range invalidConstraints {
+		t.Run(constraint, func(t *testing.T) {
+			set, err := ParsePoetryConstraint(constraint)
+			var specifierErr *SpecifierError
+			if !errors.As(err, &specifierErr) || specifierErr.Specifier != constraint {
+				t.Errorf("%s should be invalid, got %s, %v", constraint, set, err)
+			}
+		})
+	}
+
+	if _, err := ParsePoetryConstraint("^1.0 || ^3.0"); !errors.Is(err, ErrNotExpressible) {
+		t.Errorf("%v should be a not expressible error", err)
+	}
+	if _, err := ParsePoetryConstraint("^1.2 ||"); !errors.Is(err, ErrInvalidSpecifier) || errors.Is(err, ErrNotExpressible) {
+		t.Errorf("%v should be an invalid specifier error only", err)
+	}
+}
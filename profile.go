@@ -0,0 +1,195 @@
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Profile is a target environment such as CPython 3.12 on manylinux 2.28 aarch64, which may differ
+// from the running one. It drives both marker evaluation by Environment and wheel compatibility
+// checks by Tags.
+type Profile struct {
+	name string
+	// implementation is the abbreviation of interpreter, 'cp' or 'pp'.
+	implementation string
+	python         [2]int
+	// platform is the most specific platform tag, e.g. 'manylinux_2_28_aarch64' or 'win_amd64'.
+	platform string
+	// system is the kind of platform, one of 'manylinux', 'musllinux', 'macosx' or 'win'.
+	system        string
+	systemVersion [2]int
+	arch          string
+}
+
+// NewProfile creates a profile from an interpreter tag such as 'cp312' or 'pp310' and the most
+// specific platform tag, which is one of
+//
+//   - manylinux_<glibc major>_<glibc minor>_<arch>, e.g. 'manylinux_2_17_x86_64'
+//   - musllinux_<musl major>_<musl minor>_<arch>, e.g. 'musllinux_1_2_aarch64'
+//   - macosx_<macOS major>_<macOS minor>_<arch>, e.g. 'macosx_11_0_arm64'
+//   - win32, win_amd64 or win_arm64
+//
+// The profile is named like '<interpreter>-<platform>', e.g. 'cp312-manylinux_2_28_aarch64'. An
+// error is a *ProfileError of ErrInvalidProfile.
+func NewProfile(interpreter, platform string) (*Profile, error) {
+	p := &Profile{name: interpreter + "-" + platform, platform: platform}
+
+	if len(interpreter) < 4 || !isDigits(interpreter[2:]) {
+		return nil, p.fail(ComponentPython, fmt.Errorf("invalid interpreter '%s'", interpreter))
+	}
+	switch p.implementation = interpreter[:2]; p.implementation {
+	case "cp", "pp":
+	default:
+		return nil, p.fail(ComponentPython, fmt.Errorf("unsupported interpreter '%s'", interpreter))
+	}
+	p.python[0] = int(interpreter[2] - '0')
+	minor, err := strconv.Atoi(interpreter[3:])
+	if err != nil {
+		return nil, p.fail(ComponentPython, err)
+	}
+	p.python[1] = minor
+
+	switch platform {
+	case "win32", "win_amd64", "win_arm64":
+		p.system, p.arch = "win", strings.TrimPrefix(strings.TrimPrefix(platform, "win"), "_")
+		return p, nil
+	}
+
+	parts := strings.SplitN(platform, "_", 4)
+	if len(parts) != 4 || parts[3] == "" {
+		return nil, p.fail(ComponentPlatform, fmt.Errorf("invalid platform '%s'", platform))
+	}
+	switch p.system = parts[0]; p.system {
+	case "manylinux", "musllinux", "macosx":
+	default:
+		return nil, p.fail(ComponentPlatform, fmt.Errorf("unsupported platform '%s'", platform))
+	}
+	if p.systemVersion[0], p.systemVersion[1], err = parseVersionPair(parts[1], parts[2]); err != nil {
+		return nil, p.fail(ComponentPlatform, err)
+	}
+	p.arch = parts[3]
+
+	return p, nil
+}
+
+func (p *Profile) fail(component string, err error) error {
+	return &ProfileError{Profile: p.name, Component: component, Kind: ErrInvalidProfile, Err: err}
+}
+
+func (p *Profile) String() string {
+	return fmt.Sprintf("Profile<%s>", p.name)
+}
+
+func (p *Profile) Name() string {
+	return p.name
+}
+
+// Environment returns the marker environment of profile, the python patch version is assumed to be 0
+// and PlatformRelease and PlatformVersion are left empty since they are unknown.
+func (p *Profile) Environment() Environment {
+	python := fmt.Sprintf("%d.%d", p.python[0], p.python[1])
+	env := Environment{
+		ImplementationName:           "cpython",
+		ImplementationVersion:        python + ".0",
+		PythonFullVersion:            python + ".0",
+		PlatformPythonImplementation: "CPython",
+		PythonVersion:                python,
+	}
+	if p.implementation == "pp" {
+		env.ImplementationName = "pypy"
+		env.ImplementationVersion = pypyVersion + ".0"
+		env.PlatformPythonImplementation = "PyPy"
+	}
+
+	switch p.system {
+	case "manylinux", "musllinux":
+		env.OSName, env.SysPlatform, env.PlatformSystem = "posix", "linux", "Linux"
+		env.PlatformMachine = p.arch
+	case "macosx":
+		env.OSName, env.SysPlatform, env.PlatformSystem = "posix", "darwin", "Darwin"
+		env.PlatformMachine = p.arch
+	case "win":
+		env.OSName, env.SysPlatform, env.PlatformSystem = "nt", "win32", "Windows"
+		env.PlatformMachine = map[string]string{"32": "x86", "amd64": "AMD64", "arm64": "ARM64"}[p.arch]
+	}
+
+	return env
+}
+
+// pypyVersion is the version of pypy assumed by profiles, which all pypy 3.x releases since 2020
+// share in their abi tags.
+const pypyVersion = "7.3"
+
+// Tags returns the wheel tags supported by profile in order of preference, the same as packaging's
+// sys_tags would return on the target environment.
+func (p *Profile) Tags() []Tag {
+	var platforms []string
+	switch p.system {
+	case "manylinux":
+		platforms = manylinuxTags(p.systemVersion[1], p.arch)
+	case "musllinux":
+		platforms = musllinuxTags(p.systemVersion[0], p.systemVersion[1], p.arch)
+	case "macosx":
+		platforms = macosTags(p.systemVersion[0], p.systemVersion[1], p.arch)
+	default:
+		platforms = []string{p.platform}
+	}
+
+	major, minor := p.python[0], p.python[1]
+	if p.implementation == "pp" {
+		interpreter := fmt.Sprintf("pp%d%d", major, minor)
+		abi := fmt.Sprintf("pypy%d%d_pp%s", major, minor, strings.ReplaceAll(pypyVersion, ".", ""))
+		return genericTags(major, minor, interpreter, abi, platforms)
+	}
+
+	return cpythonTags(major, minor, platforms)
+}
+
+// Supports reports whether the wheel can be installed on profile.
+func (p *Profile) Supports(w *Wheel) bool {
+	return w.IsSupported(p.Tags())
+}
+
+// catalogue of profiles, the interpreters are crossed with the platforms.
+var (
+	profileInterpreters = []string{"cp38", "cp39", "cp310", "cp311", "cp312", "cp313", "pp39", "pp310"}
+	profilePlatforms    = []string{
+		"manylinux_2_17_x86_64", "manylinux_2_17_aarch64", "manylinux_2_28_x86_64", "manylinux_2_28_aarch64",
+		"musllinux_1_2_x86_64", "musllinux_1_2_aarch64",
+		"macosx_10_9_x86_64", "macosx_11_0_arm64",
+		"win32", "win_amd64", "win_arm64",
+	}
+)
+
+var profiles = func() []*Profile {
+	var profiles []*Profile
+	for _, interpreter := range profileInterpreters {
+		for _, platform := range profilePlatforms {
+			p, err := NewProfile(interpreter, platform)
+			if err != nil {
+				panic(err)
+			}
+			profiles = append(profiles, p)
+		}
+	}
+
+	return profiles
+}()
+
+// Profiles returns the built-in profiles, which are CPython 3.8 to 3.13 and PyPy 3.9 and 3.10 on
+// manylinux (glibc 2.17 and 2.28), musllinux 1.2, macOS and Windows of common architectures.
+func Profiles() []*Profile {
+	return append([]*Profile(nil), profiles...)
+}
+
+// LookupProfile returns the built-in profile by name, e.g. 'cp312-manylinux_2_28_aarch64'.
+func LookupProfile(name string) (*Profile, bool) {
+	for _, p := range profiles {
+		if p.name == name {
+			return p, true
+		}
+	}
+
+	return nil, false
+}
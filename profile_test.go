@@ -0,0 +1,171 @@
+package version
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewProfile(t *testing.T) {
+	var profileCases = []struct {
+		interpreter string
+		platform    string
+		// component is the failing component, it's empty for a valid profile
+		component string
+	}{
+		{"cp312", "manylinux_2_28_aarch64", ""},
+		{"pp310", "win_amd64", ""},
+		{"cp39", "macosx_10_9_x86_64", ""},
+		{"py3", "win32", ComponentPython},
+		{"cp3", "win32", ComponentPython},
+		{"jy27", "win32", ComponentPython},
+		{"cp312", "linux_x86_64", ComponentPlatform},
+		{"cp312", "manylinux2014_x86_64", ComponentPlatform},
+		{"cp312", "manylinux_2_x_x86_64", ComponentPlatform},
+		{"cp312", "win_ia64", ComponentPlatform},
+	}
+
+	for _, c := range profileCases {
+		t.Run(c.interpreter+"-"+c.platform, func(t *testing.T) {
+			p, err := NewProfile(c.interpreter, c.platform)
+			if c.component == "" {
+				if err != nil || p.Name() != c.interpreter+"-"+c.platform {
+					t.Errorf("%v(actual) != %s-%s(expected), %v", p, c.interpreter, c.platform, err)
+				}
+				return
+			}
+
+			var profileErr *ProfileError
+			if !errors.Is(err, ErrInvalidProfile) || !errors.As(err, &profileErr) || profileErr.Component != c.component {
+				t.Errorf("%v should be an invalid profile error of %s", err, c.component)
+			}
+		})
+	}
+}
+
+func TestProfileEnvironment(t *testing.T) {
+	var environmentCases = []struct {
+		name     string
+		expected Environment
+	}{
+		{"cp312-manylinux_2_28_aarch64", Environment{
+			ImplementationName:           "cpython",
+			ImplementationVersion:        "3.12.0",
+			OSName:                       "posix",
+			PlatformMachine:              "aarch64",
+			PlatformSystem:               "Linux",
+			PythonFullVersion:            "3.12.0",
+			PlatformPythonImplementation: "CPython",
+			PythonVersion:                "3.12",
+			SysPlatform:                  "linux",
+		}},
+		{"pp310-win_amd64", Environment{
+			ImplementationName:           "pypy",
+			ImplementationVersion:        "7.3.0",
+			OSName:                       "nt",
+			PlatformMachine:              "AMD64",
+			PlatformSystem:               "Windows",
+			PythonFullVersion:            "3.10.0",
+			PlatformPythonImplementation: "PyPy",
+			PythonVersion:                "3.10",
+			SysPlatform:                  "win32",
+		}},
+		{"cp38-macosx_11_0_arm64", Environment{
+			ImplementationName:           "cpython",
+			ImplementationVersion:        "3.8.0",
+			OSName:                       "posix",
+			PlatformMachine:              "arm64",
+			PlatformSystem:               "Darwin",
+			PythonFullVersion:            "3.8.0",
+			PlatformPythonImplementation: "CPython",
+			PythonVersion:                "3.8",
+			SysPlatform:                  "darwin",
+		}},
+	}
+
+	for _, c := range environmentCases {
+		t.Run(c.name, func(t *testing.T) {
+			p, ok := LookupProfile(c.name)
+			if !ok {
+				t.Errorf("%s should be a built-in profile", c.name)
+				return
+			}
+			if env := p.Environment(); env != c.expected {
+				t.Errorf("%+v(actual) != %+v(expected)", env, c.expected)
+			}
+		})
+	}
+}
+
+func TestProfileTags(t *testing.T) {
+	p, _ := LookupProfile("pp39-musllinux_1_2_aarch64")
+	tags := p.Tags()
+
+	expected := "pp39-pypy39_pp73-musllinux_1_2_aarch64 pp39-pypy39_pp73-musllinux_1_1_aarch64"
+	if actual := joinTags(tags[:2]); actual != expected {
+		t.Errorf("%s(actual) != %s(expected)", actual, expected)
+	}
+	if actual := tags[4].String(); actual != "pp39-none-musllinux_1_2_aarch64" {
+		t.Errorf("%s(actual) != pp39-none-musllinux_1_2_aarch64(expected)", actual)
+	}
+
+	p, _ = LookupProfile("cp311-win32")
+	if actual := joinTags(p.Tags()[:3]); actual != "cp311-cp311-win32 cp311-abi3-win32 cp311-none-win32" {
+		t.Errorf("%s(actual) != cp311-cp311-win32 cp311-abi3-win32 cp311-none-win32(expected)", actual)
+	}
+}
+
+func TestProfileSupports(t *testing.T) {
+	var supportCases = []struct {
+		profile  string
+		filename string
+		expected bool
+	}{
+		{"cp312-manylinux_2_28_aarch64", "numpy-1.26.2-cp312-cp312-manylinux_2_17_aarch64.manylinux2014_aarch64.whl", true},
+		{"cp312-manylinux_2_17_aarch64", "numpy-1.26.2-cp312-cp312-manylinux_2_28_aarch64.whl", false},
+		{"cp312-manylinux_2_28_aarch64", "numpy-1.26.2-cp312-cp312-musllinux_1_1_aarch64.whl", false},
+		{"cp312-musllinux_1_2_x86_64", "numpy-1.26.2-cp312-cp312-musllinux_1_1_x86_64.whl", true},
+		{"cp313-macosx_11_0_arm64", "cryptography-41.0.7-cp37-abi3-macosx_10_12_universal2.whl", true},
+		{"cp313-macosx_10_9_x86_64", "numpy-1.26.2-cp313-cp313-macosx_11_0_x86_64.whl", false},
+		{"pp310-win_amd64", "numpy-1.26.2-pp39-pypy39_pp73-win_amd64.whl", false},
+		{"pp310-win_amd64", "six-1.16.0-py2.py3-none-any.whl", true},
+		{"cp38-win_arm64", "numpy-1.26.2-cp38-cp38-win_amd64.whl", false},
+	}
+
+	for _, c := range supportCases {
+		t.Run(c.profile+"/"+c.filename, func(t *testing.T) {
+			p, ok := LookupProfile(c.profile)
+			if !ok {
+				t.Errorf("%s should be a built-in profile", c.profile)
+				return
+			}
+			whl, err := NewWheel(c.filename)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if p.Supports(whl) != c.expected {
+				t.Errorf("%v(actual) != %v(expected)", p.Supports(whl), c.expected)
+			}
+		})
+	}
+
+	if n := len(Profiles()); n != 88 {
+		t.Errorf("%d(actual) != 88(expected)", n)
+	}
+	if _, ok := LookupProfile("cp27-win32"); ok {
+		t.Errorf("cp27-win32 should not be a built-in profile")
+	}
+}
+
+func TestProfileMarker(t *testing.T) {
+	r, _ := ParseRequirement(`uvloop>=0.17; sys_platform != "win32" and platform_machine == "aarch64"`)
+
+	linux, _ := LookupProfile("cp312-manylinux_2_28_aarch64")
+	if ok, err := r.Marker.Evaluate(linux.Environment()); !ok || err != nil {
+		t.Errorf("%s should apply to %s, %v", r, linux, err)
+	}
+	windows, _ := LookupProfile("cp312-win_arm64")
+	if ok, err := r.Marker.Evaluate(windows.Environment()); ok || err != nil {
+		t.Errorf("%s should not apply to %s, %v", r, windows, err)
+	}
+}
@@ -0,0 +1,344 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Requirement is a dependency specifier of pep-508, e.g. 'requests[security]>=2.8.1; python_version < "2.7"'
+// or 'pip @ https://github.com/pypa/pip/archive/1.3.1.zip', for details:
+// https://peps.python.org/pep-0508/.
+type Requirement struct {
+	// Name is the package name as written, use CanonicalizePackage to compare names.
+	Name string
+	// Extras is the optional features canonicalized by CanonicalizeExtra, sorted and without duplicates.
+	Extras []string
+	// Specifier is the version specifiers, it's empty if there are none.
+	Specifier *SpecifierSet
+	// URL is the direct reference following '@', it's empty if there is none.
+	URL string
+	// Marker is the environment marker following ';', it's nil if there is none.
+	Marker *Marker
+}
+
+// ParseRequirement parses a dependency specifier following the grammar of packaging, see
+// https://github.com/pypa/packaging/blob/23.0/src/packaging/_parser.py. A *RequirementError is
+// returned with the position where parsing fails.
+func ParseRequirement(requirement string) (*Requirement, error) {
+	p := &requirementParser{s: requirement}
+	return p.requirement()
+}
+
+// String returns the requirement in the form of packaging, with extras and specifiers sorted, e.g.
+// 'name[a,b]<2,>=1; os_name == "nt"' or 'name@ https://example.com/name.zip ; os_name == "nt"'. A
+// requirement without name, which only comes from a requirements file, is written as a line of the
+// file like './project[a,b] ; os_name == "nt"'.
+func (r *Requirement) String() string {
+	if r.Name == "" {
+		s := r.URL
+		if len(r.Extras) != 0 {
+			s += "[" + strings.Join(r.Extras, ",") + "]"
+		}
+		if r.Marker != nil {
+			s += " ; " + r.Marker.String()
+		}
+		return s
+	}
+
+	s := r.Name
+	if len(r.Extras) != 0 {
+		s += "[" + strings.Join(r.Extras, ",") + "]"
+	}
+	if r.Specifier != nil {
+		s += r.Specifier.String()
+	}
+	if r.URL != "" {
+		s += "@ " + r.URL
+		if r.Marker != nil {
+			s += " "
+		}
+	}
+	if r.Marker != nil {
+		s += "; " + r.Marker.String()
+	}
+
+	return s
+}
+
+// Package returns the package of requirement with the requested extras.
+func (r *Requirement) Package() (*Package, error) {
+	p, err := NewPackage(r.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.WithExtras(r.Extras...)
+}
+
+// IsActive reports whether the requirement applies in env when the extras are requested, i.e. it
+// has no marker, or its marker is satisfied with 'extra' being any of extras, or being empty if no
+// extras are requested. This is how pip selects the dependencies of a distribution.
+func (r *Requirement) IsActive(env Environment, extras ...string) (bool, error) {
+	if r.Marker == nil {
+		return true, nil
+	}
+
+	if len(extras) == 0 {
+		extras = []string{""}
+	}
+	for _, extra := range extras {
+		env.Extra = extra
+		ok, err := r.Marker.Evaluate(env)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+
+	return false, nil
+}
+
+// requirementParser is a recursive-descent parser of pep-508, positions in errors are byte offsets
+// of the input.
+type requirementParser struct {
+	s string
+	i int
+	// [specStart, specEnd) is the span of version specifiers, it's empty after the name and extras if
+	// there are no specifiers.
+	specStart, specEnd int
+}
+
+// requirement = WS? IDENTIFIER WS? extras WS? (AT URL (WS marker?)? | specifier WS? marker?)
+func (p *requirementParser) requirement() (*Requirement, error) {
+	p.spaces()
+	name, err := p.name("package name")
+	if err != nil {
+		return nil, err
+	}
+	r := &Requirement{Name: name, Specifier: new(SpecifierSet)}
+
+	p.spaces()
+	if r.Extras, err = p.extras(); err != nil {
+		return nil, err
+	}
+
+	end := p.i
+	p.spaces()
+	if p.peek() == '@' {
+		p.i++
+		p.spaces()
+		start := p.i
+		for p.i < len(p.s) && !isRequirementSpace(p.s[p.i]) {
+			p.i++
+		}
+		if p.i == start {
+			return nil, p.fail(p.i, "expected URL after '@'")
+		}
+		r.URL = p.s[start:p.i]
+
+		// the URL must be followed by whitespaces before the marker, since ';' is allowed in URLs
+		p.spaces()
+		if p.i == len(p.s) {
+			return r, nil
+		}
+		if p.peek() != ';' {
+			return nil, p.fail(p.i, "expected end or semicolon (after URL and whitespace)")
+		}
+	} else {
+		p.specStart = p.i
+		if r.Specifier, err = p.specifier(); err != nil {
+			return nil, err
+		}
+		if p.specEnd = p.i; p.specStart == p.specEnd {
+			p.specStart, p.specEnd = end, end
+		}
+		p.spaces()
+		if p.i == len(p.s) {
+			return r, nil
+		}
+		if p.peek() != ';' {
+			return nil, p.fail(p.i, "expected end, semicolon, version specifier or URL")
+		}
+	}
+
+	p.i++
+	p.spaces()
+	if p.i == len(p.s) {
+		return nil, p.fail(p.i, "expected marker after semicolon")
+	}
+	if r.Marker, err = ParseMarker(p.s[p.i:]); err != nil {
+		var markerErr *MarkerError
+		if errors.As(err, &markerErr) {
+			return nil, &RequirementError{Requirement: p.s, Pos: p.i + markerErr.Pos, Kind: ErrInvalidRequirement, Err: err}
+		}
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// extras = (LEFT_BRACKET WS? (IDENTIFIER (WS? COMMA WS? IDENTIFIER)*)? WS? RIGHT_BRACKET)?
+func (p *requirementParser) extras() ([]string, error) {
+	if p.peek() != '[' {
+		return nil, nil
+	}
+	p.i++
+	p.spaces()
+
+	var extras []string
+	for p.peek() != ']' {
+		extra, err := p.name("extra name")
+		if err != nil {
+			return nil, err
+		}
+		extras = append(extras, extra)
+
+		p.spaces()
+		switch p.peek() {
+		case ',':
+			p.i++
+			p.spaces()
+			if p.peek() == ']' {
+				return nil, p.fail(p.i, "expected extra name after comma")
+			}
+		case ']':
+		default:
+			return nil, p.fail(p.i, "expected comma or closing bracket")
+		}
+	}
+	p.i++
+
+	return canonicalExtras(extras), nil
+}
+
+// canonicalExtras canonicalizes extras by CanonicalizeExtra, and sorts them without duplicates.
+func canonicalExtras(extras []string) []string {
+	var canonical []string
+	seen := make(map[string]bool)
+	for _, extra := range extras {
+		if extra = CanonicalizeExtra(extra); !seen[extra] {
+			seen[extra] = true
+			canonical = append(canonical, extra)
+		}
+	}
+	sort.Strings(canonical)
+
+	return canonical
+}
+
+// specifier = LEFT_PARENTHESIS WS? version_many WS? RIGHT_PARENTHESIS | version_many
+// version_many = (SPECIFIER (WS? COMMA WS? SPECIFIER)*)?
+func (p *requirementParser) specifier() (*SpecifierSet, error) {
+	paren := p.peek() == '('
+	if paren {
+		p.i++
+		p.spaces()
+	}
+
+	set := new(SpecifierSet)
+	for isOperatorLetter(p.peek()) {
+		spec, err := p.version()
+		if err != nil {
+			return nil, err
+		}
+		set.specs = append(set.specs, spec)
+
+		start := p.i
+		p.spaces()
+		if p.peek() != ',' {
+			p.i = start
+			break
+		}
+		p.i++
+		p.spaces()
+		if !isOperatorLetter(p.peek()) {
+			return nil, p.fail(p.i, "expected version specifier after comma")
+		}
+	}
+
+	if paren {
+		p.spaces()
+		if p.peek() != ')' {
+			return nil, p.fail(p.i, "expected closing parenthesis")
+		}
+		p.i++
+	}
+
+	return set, nil
+}
+
+// version parses a single specifier such as '>= 1.0'.
+func (p *requirementParser) version() (*Specifier, error) {
+	start := p.i
+	for isOperatorLetter(p.peek()) {
+		p.i++
+	}
+	p.spaces()
+	versionStart := p.i
+	for p.i < len(p.s) && !isRequirementSpace(p.s[p.i]) && !strings.ContainsRune(",;)", rune(p.s[p.i])) {
+		p.i++
+	}
+	if p.i == versionStart {
+		return nil, p.fail(p.i, "expected version after operator")
+	}
+
+	spec, err := ParseSpecifier(p.s[start:p.i])
+	if err != nil {
+		return nil, p.fail(start, "%w", err)
+	}
+	return spec, nil
+}
+
+// name parses an identifier which must be a valid package name.
+func (p *requirementParser) name(subject string) (string, error) {
+	start := p.i
+	for isAlnum(p.peek()) || isSeparator(p.peek()) {
+		p.i++
+	}
+	if p.i == start {
+		return "", p.fail(start, "expected %s", subject)
+	}
+
+	name := p.s[start:p.i]
+	if !packageNameRe.MatchString(name) {
+		return "", &RequirementError{
+			Requirement: p.s,
+			Pos:         start,
+			Kind:        ErrInvalidRequirement,
+			Err:         &NameError{Name: name, Kind: ErrInvalidName},
+		}
+	}
+	return name, nil
+}
+
+func (p *requirementParser) peek() byte {
+	if p.i < len(p.s) {
+		return p.s[p.i]
+	}
+	return 0
+}
+
+// spaces skips the whitespaces of pep-508, which are spaces and tabs only.
+func (p *requirementParser) spaces() {
+	for p.i < len(p.s) && isRequirementSpace(p.s[p.i]) {
+		p.i++
+	}
+}
+
+func (p *requirementParser) fail(pos int, format string, args ...interface{}) error {
+	return &RequirementError{
+		Requirement: p.s,
+		Pos:         pos,
+		Kind:        ErrInvalidRequirement,
+		Err:         fmt.Errorf(format, args...),
+	}
+}
+
+func isRequirementSpace(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+func isOperatorLetter(c byte) bool {
+	return c == '~' || c == '=' || c == '!' || c == '<' || c == '>'
+}
@@ -0,0 +1,121 @@
+package version
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseRequirement(t *testing.T) {
+	var requirementCases = []struct {
+		requirement string
+		name        string
+		extras      string
+		specifier   string
+		url         string
+		marker      string
+		expected    string
+	}{
+		{"A", "A", "", "", "", "", "A"},
+		{"A.B-C_D", "A.B-C_D", "", "", "", "", "A.B-C_D"},
+		{"  aa  ", "aa", "", "", "", "", "aa"},
+		{"name>=3", "name", "", ">=3", "", "", "name>=3"},
+		{"name >= 3, < 4", "name", "", "<4,>=3", "", "", "name<4,>=3"},
+		{"name (>=3,<4)", "name", "", "<4,>=3", "", "", "name<4,>=3"},
+		{"name[]", "name", "", "", "", "", "name"},
+		{"name[quux, strange];python_version<'2.7' and platform_version=='2'", "name", "quux,strange", "", "",
+			`python_version < "2.7" and platform_version == "2"`,
+			`name[quux,strange]; python_version < "2.7" and platform_version == "2"`},
+		{"name [ b , a , b ]", "name", "a,b", "", "", "", "name[a,b]"},
+		{"foo[Socks,socks,Use_Chardet]", "foo", "socks,use-chardet", "", "", "", "foo[socks,use-chardet]"},
+		{"requests[security,socks]>=2.8.1,==2.8.* ; python_version < \"2.7\"", "requests", "security,socks",
+			"==2.8.*,>=2.8.1", "", "python_version < \"2.7\"",
+			"requests[security,socks]==2.8.*,>=2.8.1; python_version < \"2.7\""},
+		{"pip @ https://github.com/pypa/pip/archive/1.3.1.zip#sha1=da9234ee", "pip", "", "",
+			"https://github.com/pypa/pip/archive/1.3.1.zip#sha1=da9234ee", "",
+			"pip@ https://github.com/pypa/pip/archive/1.3.1.zip#sha1=da9234ee"},
+		{"name@http://foo.com ; os_name=='a'", "name", "", "", "http://foo.com", `os_name == "a"`,
+			`name@ http://foo.com ; os_name == "a"`},
+		{"name @ file:///a;b", "name", "", "", "file:///a;b", "", "name@ file:///a;b"},
+		{"name===arbitrary", "name", "", "===arbitrary", "", "", "name===arbitrary"},
+		{"name==1.0.*;os_name=='a'", "name", "", "==1.0.*", "", `os_name == "a"`, `name==1.0.*; os_name == "a"`},
+	}
+
+	for _, c := range requirementCases {
+		t.Run(c.requirement, func(t *testing.T) {
+			r, err := ParseRequirement(c.requirement)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			extras := ""
+			for i, extra := range r.Extras {
+				if i > 0 {
+					extras += ","
+				}
+				extras += extra
+			}
+			marker := ""
+			if r.Marker != nil {
+				marker = r.Marker.String()
+			}
+			if r.Name != c.name || extras != c.extras || r.Specifier.String() != c.specifier || r.URL != c.url || marker != c.marker {
+				t.Errorf("%s|%s|%s|%s|%s(actual) != %s|%s|%s|%s|%s(expected)",
+					r.Name, extras, r.Specifier, r.URL, marker, c.name, c.extras, c.specifier, c.url, c.marker)
+			}
+			if r.String() != c.expected {
+				t.Errorf("%s(actual) != %s(expected)", r, c.expected)
+			}
+		})
+	}
+}
+
+func TestParseRequirementError(t *testing.T) {
+	var errorCases = []struct {
+		requirement string
+		pos         int
+	}{
+		{"", 0},
+		{"  ", 2},
+		{"name-", 0},
+		{"@ http://foo.com", 0},
+		{"name 1.0", 5},
+		{"name[bar", 8},
+		{"name[bar baz]", 9},
+		{"name[bar,]", 9},
+		{"name[-bar]", 5},
+		{"name>=", 6},
+		{"name>=1.0,", 10},
+		{"name>=1.0 <2", 10},
+		{"name~=1", 4},
+		{"name>=1.0,~=1", 10},
+		{"name (>=1.0", 11},
+		{"name @ ", 7},
+		{"name @ http://foo.com bar", 22},
+		{"name;", 5},
+		{"name>=1.0 ; ", 12},
+		{"name; os_name", 13},
+		{"name; os_name == 'a' or", 23},
+		{"name; (os_name == 'a'", 21},
+		{"name @ http://foo.com ; os_name === \"a", 36},
+	}
+
+	for _, c := range errorCases {
+		t.Run(c.requirement, func(t *testing.T) {
+			_, err := ParseRequirement(c.requirement)
+
+			var requirementErr *RequirementError
+			if !errors.Is(err, ErrInvalidRequirement) || !errors.As(err, &requirementErr) {
+				t.Errorf("%v should be an invalid requirement error", err)
+				return
+			}
+			if requirementErr.Pos != c.pos {
+				t.Errorf("%d(actual) != %d(expected), %v", requirementErr.Pos, c.pos, err)
+			}
+		})
+	}
+
+	if _, err := ParseRequirement("name[-bar]"); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("%v should be an invalid name error", err)
+	}
+}
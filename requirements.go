@@ -0,0 +1,498 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// RequirementsFile is a parsed requirements file of pip together with the files it includes, for the
+// format: https://pip.pypa.io/en/stable/reference/requirements-file-format/.
+type RequirementsFile struct {
+	// Requirements is the requirements and constraints of all the files in order of appearance.
+	Requirements []*FileRequirement
+	// IndexURL is the last '--index-url', it's empty if there is none.
+	IndexURL       string
+	ExtraIndexURLs []string
+	FindLinks      []string
+	TrustedHosts   []string
+	NoIndex        bool
+	// Pre, PreferBinary and RequireHashes are set by '--pre', '--prefer-binary' and '--require-hashes'.
+	Pre           bool
+	PreferBinary  bool
+	RequireHashes bool
+	// OnlyBinary and NoBinary are the package names of '--only-binary' and '--no-binary' in order,
+	// a comma separated value is split, and ':all:' and ':none:' are kept as they are.
+	OnlyBinary []string
+	NoBinary   []string
+	// Features is the values of '--use-feature'.
+	Features []string
+}
+
+// FileRequirement is a requirement line of a requirements file.
+type FileRequirement struct {
+	// Requirement is the parsed requirement. For a URL or a local path instead of a pep-508 requirement,
+	// its URL is the location and its name is taken from the '#egg=' fragment or the wheel filename, or
+	// left empty if neither exists.
+	Requirement *Requirement
+	// Version is the version in the wheel filename of a URL or a local path, it's empty otherwise.
+	Version string
+	// Editable is true for '-e' requirements.
+	Editable bool
+	// Constraint is true for the requirements of files included by '-c'.
+	Constraint bool
+	// Hashes is the '--hash' options in the form of '<algorithm>:<digest>'.
+	Hashes []string
+	// File is the name of the file containing the requirement, and Line is the line number where the
+	// requirement starts, counting from 1.
+	File string
+	Line int
+}
+
+// ParseRequirementsFile parses the requirements file named name in fsys, the files included by '-r'
+// and '-c' are resolved relative to the including file, and a cyclic include is an error of
+// ErrIncludeCycle. Lines are processed like pip: continuations are joined, comments are removed and
+// '${VAR}' is replaced by the environment variable if it's set.
+func ParseRequirementsFile(fsys fs.FS, name string) (*RequirementsFile, error) {
+	p := &requirementsParser{fsys: fsys, file: new(RequirementsFile)}
+	if err := p.parse(path.Clean(name), false); err != nil {
+		return nil, err
+	}
+
+	return p.file, nil
+}
+
+type requirementsParser struct {
+	fsys fs.FS
+	file *RequirementsFile
+	// stack is the names of the files being parsed, for detecting cyclic includes.
+	stack []string
+}
+
+func (p *requirementsParser) parse(name string, constraint bool) error {
+	for _, parsing := range p.stack {
+		if parsing == name {
+			return &RequirementsError{File: name, Kind: ErrIncludeCycle, Err: fmt.Errorf("included by '%s'", strings.Join(p.stack, "' -> '"))}
+		}
+	}
+	p.stack = append(p.stack, name)
+	defer func() {
+		p.stack = p.stack[:len(p.stack)-1]
+	}()
+
+	content, err := fs.ReadFile(p.fsys, name)
+	if err != nil {
+		return &RequirementsError{File: name, Kind: ErrInvalidRequirementsFile, Err: err}
+	}
+
+	for _, line := range splitRequirementsLines(string(content)) {
+		if err := p.line(name, line, constraint); err != nil {
+			var requirementsErr *RequirementsError
+			if errors.As(err, &requirementsErr) {
+				return err
+			}
+			return &RequirementsError{File: name, Line: line.number, Kind: ErrInvalidRequirementsFile, Err: err}
+		}
+	}
+
+	return nil
+}
+
+func (p *requirementsParser) line(name string, line *requirementsLine, constraint bool) error {
+	args, options := line.args()
+	opts, err := parseRequirementsOptions(options)
+	if err != nil {
+		return err
+	}
+
+	if len(args) != 0 {
+		r, version, err := parseRequirementLine(expandEnv(line.requirement(args)), false)
+		if err != nil {
+			return err
+		}
+
+		// like pip, the options other than '--hash' are ignored after a requirement
+		req := &FileRequirement{Requirement: r, Version: version, Constraint: constraint, File: name, Line: line.number}
+		for _, opt := range opts {
+			if opt.name == "--hash" {
+				req.Hashes = append(req.Hashes, opt.value)
+			}
+		}
+		p.file.Requirements = append(p.file.Requirements, req)
+		return nil
+	}
+
+	for _, opt := range opts {
+		switch opt.name {
+		case "--requirement", "--constraint":
+			if strings.Contains(opt.value, "://") {
+				return fmt.Errorf("unsupported remote requirements file '%s'", opt.value)
+			}
+			included := path.Join(path.Dir(name), opt.value)
+			// like pip, requirements included by a constraints file are not constraints
+			if err := p.parse(included, opt.name == "--constraint"); err != nil {
+				return err
+			}
+		case "--editable":
+			r, version, err := parseRequirementLine(opt.value, true)
+			if err != nil {
+				return err
+			}
+			p.file.Requirements = append(p.file.Requirements, &FileRequirement{
+				Requirement: r,
+				Version:     version,
+				Editable:    true,
+				Constraint:  constraint,
+				File:        name,
+				Line:        line.number,
+			})
+		case "--index-url":
+			p.file.IndexURL = opt.value
+		case "--extra-index-url":
+			p.file.ExtraIndexURLs = append(p.file.ExtraIndexURLs, opt.value)
+		case "--find-links":
+			p.file.FindLinks = append(p.file.FindLinks, opt.value)
+		case "--trusted-host":
+			p.file.TrustedHosts = append(p.file.TrustedHosts, opt.value)
+		case "--no-index":
+			p.file.NoIndex = true
+		case "--pre":
+			p.file.Pre = true
+		case "--prefer-binary":
+			p.file.PreferBinary = true
+		case "--require-hashes":
+			p.file.RequireHashes = true
+		case "--only-binary":
+			p.file.OnlyBinary = appendFormatControl(p.file.OnlyBinary, opt.value)
+		case "--no-binary":
+			p.file.NoBinary = appendFormatControl(p.file.NoBinary, opt.value)
+		case "--use-feature":
+			p.file.Features = append(p.file.Features, opt.value)
+		case "--hash", "--global-option", "--config-settings":
+			return fmt.Errorf("option '%s' must follow a requirement", opt.name)
+		}
+	}
+
+	return nil
+}
+
+// appendFormatControl appends the comma separated package names of '--only-binary' or '--no-binary'.
+func appendFormatControl(names []string, value string) []string {
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// parseRequirementLine parses the requirement part of a line, which is either a pep-508 requirement
+// or a URL or local path optionally followed by '; <marker>'. Editable requirements are always
+// locations. The version of a wheel filename is returned for a location, which isn't added to the
+// specifiers since pep-508 doesn't allow both specifiers and a URL.
+func parseRequirementLine(line string, editable bool) (*Requirement, string, error) {
+	if !editable {
+		r, err := ParseRequirement(line)
+		if err == nil || !isRequirementLocation(line) {
+			return r, "", err
+		}
+	}
+
+	r := &Requirement{Specifier: new(SpecifierSet)}
+	location := line
+	// the separator of markers needs a space for URLs, since ';' is allowed in URLs
+	separator := ";"
+	if strings.Contains(line, "://") {
+		separator = "; "
+	}
+	if i := strings.Index(line, separator); i >= 0 {
+		marker, err := ParseMarker(strings.TrimSpace(line[i+len(separator):]))
+		if err != nil {
+			return nil, "", err
+		}
+		location, r.Marker = strings.TrimSpace(line[:i]), marker
+	}
+
+	// extras of local paths, e.g. './project[test]'
+	if !strings.Contains(location, "://") && strings.HasSuffix(location, "]") {
+		if i := strings.LastIndexByte(location, '['); i > 0 {
+			var extras []string
+			for _, extra := range strings.Split(location[i+1:len(location)-1], ",") {
+				if extra = strings.TrimSpace(extra); extra != "" {
+					extras = append(extras, extra)
+				}
+			}
+			r.Extras = canonicalExtras(extras)
+			location = location[:i]
+		}
+	}
+	r.URL = location
+
+	var version string
+	if match := eggFragmentRe.FindStringSubmatch(location); match != nil {
+		r.Name = match[1]
+	} else if whl, err := NewWheel(path.Base(strings.ReplaceAll(location, `\`, "/"))); err == nil {
+		r.Name, version = whl.Name, whl.Version
+	}
+
+	return r, version, nil
+}
+
+var eggFragmentRe = regexp.MustCompile(`[#&]egg=([A-Za-z0-9._-]+)`)
+
+// isRequirementLocation reports whether a requirement looks like a URL or a local path, the same as
+// pip's heuristics.
+func isRequirementLocation(line string) bool {
+	if strings.Contains(line, "://") || strings.HasPrefix(line, "file:") || strings.HasPrefix(line, ".") {
+		return true
+	}
+	if strings.ContainsAny(line, `/\`) {
+		return true
+	}
+	_, ext := splitFilename(strings.Fields(line)[0])
+	return StandardExt.Contains(strings.ToLower(ext))
+}
+
+// requirementsOption is an option of a line, e.g. '--hash=sha256:...' or '-r base.txt'.
+type requirementsOption struct {
+	name  string
+	value string
+	// start and end are the byte range of the option in the logical line.
+	start, end int
+}
+
+// requirementsOptions maps options to whether they take a value, the short options are aliases.
+var (
+	requirementsOptions = map[string]bool{
+		"--requirement":     true,
+		"--constraint":      true,
+		"--editable":        true,
+		"--index-url":       true,
+		"--extra-index-url": true,
+		"--find-links":      true,
+		"--trusted-host":    true,
+		"--hash":            true,
+		"--global-option":   true,
+		"--config-settings": true,
+		"--only-binary":     true,
+		"--no-binary":       true,
+		"--use-feature":     true,
+		"--no-index":        false,
+		"--pre":             false,
+		"--prefer-binary":   false,
+		"--require-hashes":  false,
+	}
+	requirementsShortOptions = map[string]string{
+		"-r": "--requirement",
+		"-c": "--constraint",
+		"-e": "--editable",
+		"-i": "--index-url",
+		"-f": "--find-links",
+	}
+	strongHashes = NewSet("sha256", "sha384", "sha512")
+)
+
+func parseRequirementsOptions(tokens []requirementsToken) ([]requirementsOption, error) {
+	var opts []requirementsOption
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i].text
+
+		opt := requirementsOption{start: tokens[i].start, end: tokens[i].end}
+		hasValue := false
+		switch {
+		case strings.HasPrefix(token, "--"):
+			opt.name = token
+			if eq := strings.IndexByte(token, '='); eq >= 0 {
+				opt.name, opt.value, hasValue = token[:eq], token[eq+1:], true
+			}
+		case len(token) >= 2 && token[0] == '-':
+			opt.name = requirementsShortOptions[token[:2]]
+			if len(token) > 2 {
+				opt.value, hasValue = token[2:], true
+			}
+		default:
+			return nil, fmt.Errorf("unexpected argument '%s'", token)
+		}
+
+		takesValue, ok := requirementsOptions[opt.name]
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("unknown option '%s'", token)
+		case takesValue && !hasValue:
+			if i+1 == len(tokens) {
+				return nil, fmt.Errorf("option '%s' requires a value", token)
+			}
+			i++
+			opt.value, opt.end = tokens[i].text, tokens[i].end
+		case !takesValue && hasValue:
+			return nil, fmt.Errorf("option '%s' takes no value", opt.name)
+		}
+
+		if opt.name == "--hash" {
+			algorithm := opt.value
+			if colon := strings.IndexByte(opt.value, ':'); colon >= 0 {
+				algorithm = opt.value[:colon]
+			}
+			if algorithm == opt.value || !strongHashes.Contains(algorithm) {
+				return nil, fmt.Errorf("invalid hash '%s', expected '<sha256|sha384|sha512>:<digest>'", opt.value)
+			}
+		}
+		opts = append(opts, opt)
+	}
+
+	return opts, nil
+}
+
+// requirementsLine is a logical line of a requirements file, continuations are joined and comments
+// are removed.
+type requirementsLine struct {
+	// number is the line number of the first physical line, counting from 1.
+	number int
+	text   string
+	// offsets maps the bytes of text to their offsets in the file content.
+	offsets []int
+}
+
+// requirementsToken is a whitespace separated token of a logical line.
+type requirementsToken struct {
+	// text is the token with environment variables expanded.
+	text string
+	// start and end are the byte range of the unexpanded token in the logical line.
+	start, end int
+}
+
+// splitRequirementsLines splits the content of a requirements file into logical lines which are not
+// empty, following pip's join_lines and ignore_comments:
+// https://github.com/pypa/pip/blob/23.0.1/src/pip/_internal/req/req_file.py#L461.
+func splitRequirementsLines(content string) []*requirementsLine {
+	var lines []*requirementsLine
+	var current *requirementsLine
+	flush := func() {
+		if current != nil {
+			current.stripComment()
+			if current.text != "" {
+				lines = append(lines, current)
+			}
+			current = nil
+		}
+	}
+
+	offset := 0
+	for number, physical := range strings.SplitAfter(content, "\n") {
+		start := offset
+		offset += len(physical)
+		physical = strings.TrimRight(physical, "\r\n")
+		if physical == "" && start == len(content) {
+			break
+		}
+
+		if current == nil {
+			current = &requirementsLine{number: number + 1}
+		}
+		comment := isRequirementsComment(physical)
+		if !comment && strings.HasSuffix(physical, `\`) {
+			current.append(strings.TrimRight(physical, `\`), start)
+			continue
+		}
+		if comment {
+			// a comment ends the continuation, it's separated by a space to be removed later
+			current.text += " "
+			current.offsets = append(current.offsets, start)
+		}
+		current.append(physical, start)
+		flush()
+	}
+	flush()
+
+	return lines
+}
+
+func (l *requirementsLine) append(text string, start int) {
+	l.text += text
+	for i := range text {
+		l.offsets = append(l.offsets, start+i)
+	}
+}
+
+// stripComment removes the comment starting from a '#' at the beginning or after whitespaces, and
+// trims the whitespaces around.
+func (l *requirementsLine) stripComment() {
+	for i := 0; i < len(l.text); i++ {
+		if l.text[i] == '#' && (i == 0 || isRequirementsSpace(l.text[i-1])) {
+			l.text, l.offsets = l.text[:i], l.offsets[:i]
+			break
+		}
+	}
+
+	end := len(l.text)
+	for end > 0 && isRequirementsSpace(l.text[end-1]) {
+		end--
+	}
+	start := 0
+	for start < end && isRequirementsSpace(l.text[start]) {
+		start++
+	}
+	l.text, l.offsets = l.text[start:end], l.offsets[start:end]
+}
+
+// tokens splits the line by whitespaces.
+func (l *requirementsLine) tokens() []requirementsToken {
+	var tokens []requirementsToken
+	for i := 0; i < len(l.text); {
+		if isRequirementsSpace(l.text[i]) {
+			i++
+			continue
+		}
+		start := i
+		for i < len(l.text) && !isRequirementsSpace(l.text[i]) {
+			i++
+		}
+		tokens = append(tokens, requirementsToken{text: expandEnv(l.text[start:i]), start: start, end: i})
+	}
+
+	return tokens
+}
+
+// requirement returns the unexpanded requirement of args in the line, the whitespaces between the
+// arguments are kept like pip, which splits the line by single spaces and joins them back.
+func (l *requirementsLine) requirement(args []requirementsToken) string {
+	return l.text[args[0].start:args[len(args)-1].end]
+}
+
+// args splits the tokens into the requirement and the options like pip's break_args_options, the
+// options start from the first token beginning with '-'.
+func (l *requirementsLine) args() (args, options []requirementsToken) {
+	tokens := l.tokens()
+	for i, token := range tokens {
+		if strings.HasPrefix(token.text, "-") {
+			return tokens[:i], tokens[i:]
+		}
+	}
+
+	return tokens, nil
+}
+
+var envVarRe = regexp.MustCompile(`\$\{([A-Z0-9_]+)\}`)
+
+// expandEnv replaces '${VAR}' with the environment variable, it's kept if the variable is empty.
+func expandEnv(s string) string {
+	return envVarRe.ReplaceAllStringFunc(s, func(v string) string {
+		if value := os.Getenv(v[2 : len(v)-1]); value != "" {
+			return value
+		}
+		return v
+	})
+}
+
+func isRequirementsComment(line string) bool {
+	return strings.HasPrefix(strings.TrimLeft(line, " \t\f\v"), "#")
+}
+
+func isRequirementsSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\f' || c == '\v' || c == '\r'
+}
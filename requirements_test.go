@@ -0,0 +1,265 @@
+package version
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// setenv sets an environment variable until the end of test, since t.Setenv needs a newer go than
+// the go.mod of this module.
+func setenv(t *testing.T, key, value string) {
+	previous, ok := os.LookupEnv(key)
+	os.Setenv(key, value)
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(key, previous)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
+
+func TestParseRequirementsFile(t *testing.T) {
+	setenv(t, "VERSION_TEST_INDEX", "https://pypi.example.com/simple")
+
+	fsys := fstest.MapFS{
+		"requirements.txt": {Data: []byte(strings.Join([]string{
+			"# the index",
+			"--index-url ${VERSION_TEST_INDEX}",
+			"--extra-index-url=https://extra.example.com/simple -f ./wheels",
+			"--trusted-host extra.example.com",
+			"",
+			"-r base.txt",
+			"-c constraints/pins.txt",
+			"requests[security,socks] >= 2.8.1, == 2.8.* ; python_version < \"2.7\"  # pinned",
+			"numpy==1.24.2 \\",
+			"    --hash=sha256:aaaa \\",
+			"    --hash sha256:bbbb",
+			"-e git+https://github.com/pypa/pip.git@22.0#egg=pip",
+			"-e ./project[test,docs]",
+			"./downloads/numpy-1.9.2-cp34-none-win32.whl",
+			"https://example.com/pkg.zip#sha1=da9234ee ; os_name == 'nt'",
+			"./pkg#egg=pkg  # comment",
+			"--find-links ${UNDEFINED_VERSION_TEST}/wheels",
+		}, "\n"))},
+		"base.txt":             {Data: []byte("six\r\n\t# indented comment\r\nattrs \\\r\n>=22\r\n")},
+		"constraints/pins.txt": {Data: []byte("urllib3<2 \\\n# a comment ends the continuation\n-r ../more.txt\n")},
+		"more.txt":             {Data: []byte("idna\\")},
+	}
+
+	file, err := ParseRequirementsFile(fsys, "requirements.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if file.IndexURL != "https://pypi.example.com/simple" || file.NoIndex {
+		t.Errorf("%s(actual) != https://pypi.example.com/simple(expected)", file.IndexURL)
+	}
+	if file.Pre || file.PreferBinary || file.RequireHashes || file.OnlyBinary != nil || file.NoBinary != nil || file.Features != nil {
+		t.Errorf("%+v should have no install options", file)
+	}
+	if strings.Join(file.ExtraIndexURLs, " ") != "https://extra.example.com/simple" ||
+		strings.Join(file.FindLinks, " ") != "./wheels ${UNDEFINED_VERSION_TEST}/wheels" || strings.Join(file.TrustedHosts, " ") != "extra.example.com" {
+		t.Errorf("%v %v %v are unexpected options", file.ExtraIndexURLs, file.FindLinks, file.TrustedHosts)
+	}
+
+	var expected = []struct {
+		requirement string
+		flags       string
+		hashes      string
+		file        string
+		line        int
+	}{
+		{"six", "", "", "base.txt", 1},
+		{"attrs>=22", "", "", "base.txt", 3},
+		{"urllib3<2", "constraint", "", "constraints/pins.txt", 1},
+		{"idna", "", "", "more.txt", 1},
+		{`requests[security,socks]==2.8.*,>=2.8.1; python_version < "2.7"`, "", "", "requirements.txt", 8},
+		{"numpy==1.24.2", "", "sha256:aaaa sha256:bbbb", "requirements.txt", 9},
+		{"pip@ git+https://github.com/pypa/pip.git@22.0#egg=pip", "editable", "", "requirements.txt", 12},
+		{"./project[docs,test]", "editable", "", "requirements.txt", 13},
+		{"numpy@ ./downloads/numpy-1.9.2-cp34-none-win32.whl", "", "", "requirements.txt", 14},
+		{`https://example.com/pkg.zip#sha1=da9234ee ; os_name == "nt"`, "", "", "requirements.txt", 15},
+		{"pkg@ ./pkg#egg=pkg", "", "", "requirements.txt", 16},
+	}
+
+	if len(file.Requirements) != len(expected) {
+		for _, r := range file.Requirements {
+			t.Log(r.Requirement)
+		}
+		t.Fatalf("%d(actual) != %d(expected)", len(file.Requirements), len(expected))
+	}
+	if file.Requirements[8].Version != "1.9.2" {
+		t.Errorf("%s(actual) != 1.9.2(expected)", file.Requirements[8].Version)
+	}
+	for i, r := range file.Requirements {
+		var flags []string
+		if r.Editable {
+			flags = append(flags, "editable")
+		}
+		if r.Constraint {
+			flags = append(flags, "constraint")
+		}
+
+		c := expected[i]
+		actual := []string{r.Requirement.String(), strings.Join(flags, " "), strings.Join(r.Hashes, " "), r.File}
+		if strings.Join(actual, "|") != strings.Join([]string{c.requirement, c.flags, c.hashes, c.file}, "|") || r.Line != c.line {
+			t.Errorf("%s:%d(actual) != %s:%d(expected)", strings.Join(actual, "|"), r.Line,
+				strings.Join([]string{c.requirement, c.flags, c.hashes, c.file}, "|"), c.line)
+		}
+	}
+}
+
+func TestRequirementLineRoundTrip(t *testing.T) {
+	var lines = []struct {
+		line    string
+		version string
+	}{
+		{"https://example.com/pkg-1.0-py3-none-any.whl", "1.0"},
+		{"./downloads/numpy-1.9.2-cp34-none-win32.whl ; os_name == 'nt'", "1.9.2"},
+		{`C:\downloads\numpy-1.9.2-cp34-none-win32.whl`, "1.9.2"},
+		{"https://example.com/pkg.zip#sha1=da9234ee ; os_name == 'nt'", ""},
+		{"git+https://github.com/pypa/pip.git@22.0#egg=pip", ""},
+		{"./local[test, docs]", ""},
+		{"./local;python_version<'3.8'", ""},
+		{"/opt/project", ""},
+		{"pip @ https://github.com/pypa/pip/archive/22.0.zip", ""},
+	}
+
+	for _, c := range lines {
+		t.Run(c.line, func(t *testing.T) {
+			r, version, err := parseRequirementLine(c.line, false)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if version != c.version {
+				t.Errorf("%s(actual) != %s(expected)", version, c.version)
+			}
+
+			again, _, err := parseRequirementLine(r.String(), false)
+			if err != nil || again.String() != r.String() {
+				t.Errorf("%s(actual) != %s(expected), %v", again, r, err)
+			}
+			if r.Name != "" {
+				if parsed, err := ParseRequirement(r.String()); err != nil || parsed.String() != r.String() {
+					t.Errorf("%s(actual) != %s(expected), %v", parsed, r, err)
+				}
+			}
+		})
+	}
+}
+
+func TestParseRequirementsFileWhitespaces(t *testing.T) {
+	fsys := fstest.MapFS{
+		"requirements.txt": {Data: []byte("pkg ;  platform_version == 'a  b'   --hash=sha256:aaaa\n")},
+	}
+
+	file, err := ParseRequirementsFile(fsys, "requirements.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := file.Requirements[0]
+	if actual := r.Requirement.String(); actual != `pkg; platform_version == "a  b"` || len(r.Hashes) != 1 {
+		t.Errorf("%s(actual) != pkg; platform_version == \"a  b\"(expected)", actual)
+	}
+}
+
+func TestParseRequirementsFileOptions(t *testing.T) {
+	fsys := fstest.MapFS{
+		"requirements.txt": {Data: []byte(strings.Join([]string{
+			"--pre --prefer-binary",
+			"--require-hashes",
+			"--only-binary=:all: --only-binary numpy,scipy",
+			"--no-binary :none:",
+			"--use-feature=truststore",
+			"six==1.16.0 --hash=sha256:aaaa",
+			"# ignored by pip after a requirement",
+			"attrs --index-url https://example.com/simple --global-option=--quiet",
+		}, "\n"))},
+	}
+
+	file, err := ParseRequirementsFile(fsys, "requirements.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !file.Pre || !file.PreferBinary || !file.RequireHashes {
+		t.Errorf("%v %v %v(actual) != true true true(expected)", file.Pre, file.PreferBinary, file.RequireHashes)
+	}
+	if actual := strings.Join(file.OnlyBinary, " "); actual != ":all: numpy scipy" {
+		t.Errorf("%s(actual) != :all: numpy scipy(expected)", actual)
+	}
+	if actual := strings.Join(append(file.NoBinary, file.Features...), " "); actual != ":none: truststore" {
+		t.Errorf("%s(actual) != :none: truststore(expected)", actual)
+	}
+	if len(file.Requirements) != 2 || file.IndexURL != "" || file.Requirements[1].Requirement.Name != "attrs" {
+		t.Errorf("options after attrs should be ignored, %+v", file)
+	}
+}
+
+func TestParseRequirementsFileError(t *testing.T) {
+	var errorCases = []struct {
+		content string
+		kind    error
+		file    string
+		line    int
+	}{
+		{"six\nrequests>=", ErrInvalidRequirementsFile, "requirements.txt", 2},
+		{"--hash=sha256:aaaa", ErrInvalidRequirementsFile, "requirements.txt", 1},
+		{"six --hash=md5:aaaa", ErrInvalidRequirementsFile, "requirements.txt", 1},
+		{"six --hash", ErrInvalidRequirementsFile, "requirements.txt", 1},
+		{"--unknown", ErrInvalidRequirementsFile, "requirements.txt", 1},
+		{"--no-index=1", ErrInvalidRequirementsFile, "requirements.txt", 1},
+		{"-r missing.txt", ErrInvalidRequirementsFile, "missing.txt", 0},
+		{"-r https://example.com/requirements.txt", ErrInvalidRequirementsFile, "requirements.txt", 1},
+		{"-r requirements.txt", ErrIncludeCycle, "requirements.txt", 0},
+		{"six\n-r nested/a.txt", ErrIncludeCycle, "nested/a.txt", 0},
+	}
+
+	for _, c := range errorCases {
+		t.Run(c.content, func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"requirements.txt": {Data: []byte(c.content)},
+				"nested/a.txt":     {Data: []byte("-r b.txt")},
+				"nested/b.txt":     {Data: []byte("-c ./a.txt")},
+			}
+
+			_, err := ParseRequirementsFile(fsys, "requirements.txt")
+			var requirementsErr *RequirementsError
+			if !errors.Is(err, c.kind) || !errors.As(err, &requirementsErr) {
+				t.Errorf("%v should be a requirements error of %v", err, c.kind)
+				return
+			}
+			if requirementsErr.File != c.file || requirementsErr.Line != c.line {
+				t.Errorf("%s:%d(actual) != %s:%d(expected), %v", requirementsErr.File, requirementsErr.Line, c.file, c.line, err)
+			}
+		})
+	}
+}
+
+func TestSplitRequirementsLines(t *testing.T) {
+	content := "a \\\n  b # c\n\n  # d \\\ne\\"
+	lines := splitRequirementsLines(content)
+
+	var actual []string
+	for _, line := range lines {
+		if len(line.offsets) != len(line.text) {
+			t.Errorf("%d offsets for %q", len(line.offsets), line.text)
+			continue
+		}
+		// each byte of the logical line maps to the same byte of content
+		for i := range line.text {
+			if content[line.offsets[i]] != line.text[i] {
+				t.Errorf("%q at %d maps to %q", line.text[i], i, content[line.offsets[i]])
+			}
+		}
+		actual = append(actual, line.text)
+	}
+
+	if strings.Join(actual, "|") != "a   b|e" || lines[0].number != 1 || lines[1].number != 5 {
+		t.Errorf("%q(actual) != %q(expected)", actual, []string{"a   b", "e"})
+	}
+}
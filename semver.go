@@ -0,0 +1,292 @@
+package version
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SemVer is a version of semantic versioning 2.0, for details: https://semver.org/spec/v2.0.0.html.
+type SemVer struct {
+	major      number
+	minor      number
+	patch      number
+	prerelease []string
+	build      []string
+}
+
+// ParseSemVer parses a semantic version strictly, so neither a preceding 'v' nor a partial version
+// such as '1.2' is allowed.
+func ParseSemVer(version string) (*SemVer, error) {
+	match := semVerRe.FindStringSubmatch(version)
+	if match == nil {
+		return nil, &VersionError{Version: version, Kind: ErrInvalidVersion}
+	}
+
+	v := &SemVer{
+		major: parseNumber(match[semVerRe.SubexpIndex("major")]),
+		minor: parseNumber(match[semVerRe.SubexpIndex("minor")]),
+		patch: parseNumber(match[semVerRe.SubexpIndex("patch")]),
+	}
+	if prerelease := match[semVerRe.SubexpIndex("prerelease")]; prerelease != "" {
+		v.prerelease = strings.Split(prerelease, ".")
+	}
+	if build := match[semVerRe.SubexpIndex("build")]; build != "" {
+		v.build = strings.Split(build, ".")
+	}
+
+	return v, nil
+}
+
+func (v *SemVer) String() string {
+	return fmt.Sprintf("SemVer<%s>", v.Complete())
+}
+
+// Complete returns complete version.
+func (v *SemVer) Complete() string {
+	version := string(v.major) + "." + string(v.minor) + "." + string(v.patch)
+	if len(v.prerelease) != 0 {
+		version += "-" + strings.Join(v.prerelease, ".")
+	}
+	if len(v.build) != 0 {
+		version += "+" + strings.Join(v.build, ".")
+	}
+
+	return version
+}
+
+// Major returns the major version, or math.MaxInt64 if it overflows int64.
+func (v *SemVer) Major() int64 {
+	return v.major.int64()
+}
+
+// Minor returns the minor version, or math.MaxInt64 if it overflows int64.
+func (v *SemVer) Minor() int64 {
+	return v.minor.int64()
+}
+
+// Patch returns the patch version, or math.MaxInt64 if it overflows int64.
+func (v *SemVer) Patch() int64 {
+	return v.patch.int64()
+}
+
+// Prerelease returns a copy of the dot separated pre-release identifiers.
+func (v *SemVer) Prerelease() []string {
+	return append([]string(nil), v.prerelease...)
+}
+
+// Build returns a copy of the dot separated build identifiers.
+func (v *SemVer) Build() []string {
+	return append([]string(nil), v.build...)
+}
+
+// Compare returns -1, 0 or +1 if v has lower, equal or higher precedence than other, build metadata
+// is ignored, see https://semver.org/spec/v2.0.0.html#spec-item-11.
+func (v *SemVer) Compare(other *SemVer) int {
+	if c := v.major.compare(other.major); c != 0 {
+		return c
+	}
+	if c := v.minor.compare(other.minor); c != 0 {
+		return c
+	}
+	if c := v.patch.compare(other.patch); c != 0 {
+		return c
+	}
+
+	// a pre-release has lower precedence than the normal version
+	switch {
+	case len(v.prerelease) == 0 && len(other.prerelease) == 0:
+		return 0
+	case len(v.prerelease) == 0:
+		return 1
+	case len(other.prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.prerelease) && i < len(other.prerelease); i++ {
+		if c := compareSemVerIdentifier(v.prerelease[i], other.prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInt(int64(len(v.prerelease)), int64(len(other.prerelease)))
+}
+
+func (v *SemVer) Less(other *SemVer) bool {
+	return v.Compare(other) < 0
+}
+
+func (v *SemVer) Equal(other *SemVer) bool {
+	return v.Compare(other) == 0
+}
+
+// compareSemVerIdentifier compares pre-release identifiers: numeric identifiers are compared as
+// integers and have lower precedence than alphanumeric ones, which are compared in ASCII order.
+func compareSemVerIdentifier(a, b string) int {
+	na, nb := isDigits(a), isDigits(b)
+	switch {
+	case na && nb:
+		return compareDigits(a, b)
+	case na:
+		return -1
+	case nb:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// Loss is a set of the information dropped or altered when converting between Version and SemVer, a
+// conversion is lossless if the loss is zero.
+type Loss int
+
+const (
+	// LossEpoch means a non-zero epoch is dropped.
+	LossEpoch Loss = 1 << iota
+	// LossRelease means non-zero release segments after the third one are dropped.
+	LossRelease
+	// LossPrerelease means the pre-release identifiers are altered, e.g. 'alpha.1' is written as
+	// 'a.1', or unknown identifiers are replaced with a dev release. It's also set if a 'dev'
+	// identifier becomes a dev release, since a dev release sorts before the pre-release it follows
+	// in pep-440 but after it in semver.
+	LossPrerelease
+	// LossPost means a post release segment is dropped.
+	LossPost
+	// LossDev means a dev release segment is dropped.
+	LossDev
+	// LossBuild means the build identifiers are altered, e.g. 'Build-5' is written as 'build.5', or
+	// dropped if it's not a valid local version.
+	LossBuild
+)
+
+var lossNames = []string{"epoch", "release", "prerelease", "post", "dev", "build"}
+
+func (l Loss) String() string {
+	if l == 0 {
+		return "none"
+	}
+
+	var names []string
+	for i, name := range lossNames {
+		if l&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	if unknown := l &^ (1<<len(lossNames) - 1); unknown != 0 {
+		names = append(names, fmt.Sprintf("Loss(%d)", int(unknown)))
+	}
+
+	return strings.Join(names, "|")
+}
+
+// SemVer converts v into a semantic version: the release is padded or truncated to three segments,
+// the pre-release is written as identifiers such as 'rc.1', and the local version becomes the build
+// metadata. Epoch, post and dev segments have no equivalent in semver and are dropped, note that a
+// dev release is not kept as a pre-release because it sorts before alpha releases in pep-440.
+func (v *Version) SemVer() (*SemVer, Loss) {
+	var loss Loss
+	if !v.epoch.isZero() {
+		loss |= LossEpoch
+	}
+
+	s := &SemVer{major: "0", minor: "0", patch: "0"}
+	for i, r := range v.release {
+		switch i {
+		case 0:
+			s.major = r
+		case 1:
+			s.minor = r
+		case 2:
+			s.patch = r
+		default:
+			if !r.isZero() {
+				loss |= LossRelease
+			}
+		}
+	}
+
+	s.prerelease = semVerPrerelease(v.pre, nil)
+	if v.post != nil {
+		loss |= LossPost
+	}
+	if v.dev != nil {
+		loss |= LossDev
+	}
+	s.build = append([]string(nil), v.local...)
+
+	return s, loss
+}
+
+// Version converts v into a pep-440 version: pre-release identifiers like 'rc.1', 'beta2' or
+// 'alpha.1.dev.3' are converted into pre and dev segments, other pre-releases are converted into
+// '.dev0' to sort before the final release. The build metadata becomes the local version. A dev
+// segment never keeps the order of semver, so it's always reported as LossPrerelease.
+func (v *SemVer) Version() (*Version, Loss) {
+	version := &Version{
+		epoch:   "0",
+		release: []number{v.major, v.minor, v.patch},
+	}
+
+	var loss Loss
+	if len(v.prerelease) != 0 {
+		var ok bool
+		if version.pre, version.dev, ok = semVerStages(v.prerelease); !ok {
+			version.pre, version.dev = nil, &segment{name: "dev", number: "0"}
+		}
+		if version.dev != nil || strings.Join(semVerPrerelease(version.pre, version.dev), ".") != strings.Join(v.prerelease, ".") {
+			loss |= LossPrerelease
+		}
+	}
+
+	if len(v.build) != 0 {
+		if build := strings.Join(v.build, "."); localVersionRe.MatchString(build) {
+			version.local = parseLocalVersion(build)
+		}
+		if strings.Join(version.local, ".") != strings.Join(v.build, ".") {
+			loss |= LossBuild
+		}
+	}
+
+	return version, loss
+}
+
+// semVerPrerelease returns the pre-release identifiers of stages, e.g. ['rc', '1', 'dev', '2'].
+func semVerPrerelease(stages ...*segment) []string {
+	var identifiers []string
+	for _, s := range stages {
+		if s != nil {
+			identifiers = append(identifiers, s.name, string(s.number))
+		}
+	}
+
+	return identifiers
+}
+
+// semVerStages converts pre-release identifiers into pre and dev segments, a stage is either an
+// identifier such as 'rc1' or 'rc', or an identifier followed by a numeric one such as 'rc.1'.
+func semVerStages(identifiers []string) (pre, dev *segment, ok bool) {
+	i := 0
+	next := func(names ...string) *segment {
+		if i == len(identifiers) {
+			return nil
+		}
+
+		id := identifiers[i]
+		letters := strings.TrimRight(id, "0123456789")
+		name := canonicalLetter(letters)
+		if !NewSet(names...).Contains(name) {
+			return nil
+		}
+		digits := id[len(letters):]
+		i++
+		if digits == "" && i < len(identifiers) && isDigits(identifiers[i]) {
+			digits = identifiers[i]
+			i++
+		}
+
+		return &segment{name: name, number: parseNumber(digits)}
+	}
+
+	pre = next("a", "b", "rc")
+	dev = next("dev")
+
+	return pre, dev, i == len(identifiers)
+}
@@ -0,0 +1,182 @@
+package version
+
+import (
+	"testing"
+)
+
+func TestParseSemVer(t *testing.T) {
+	var validSemVers = []string{
+		"0.0.4",
+		"1.2.3",
+		"10.20.30",
+		"1.1.2-prerelease+meta",
+		"1.0.0-alpha.beta.1",
+		"1.0.0-x-y-z.--",
+		"1.0.0+0.build.1-rc.10000aaa-kk-0.1",
+		"99999999999999999999999.999999999999999999.99999999999999999",
+	}
+	for _, version := range validSemVers {
+		v, err := ParseSemVer(version)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if v.Complete() != version {
+			t.Errorf("%s(actual) != %s(expected)", v.Complete(), version)
+		}
+	}
+
+	var invalidSemVers = []string{
+		"1",
+		"1.2",
+		"v1.2.3",
+		"01.1.1",
+		"1.2.3-0123",
+		"1.2.3-alpha..1",
+		"1.2.3+",
+		"1.2.3.4",
+	}
+	for _, version := range invalidSemVers {
+		if _, err := ParseSemVer(version); err == nil {
+			t.Errorf("%s should be invalid", version)
+		}
+	}
+}
+
+func TestSemVerCompare(t *testing.T) {
+	// https://semver.org/spec/v2.0.0.html#spec-item-11
+	var orderedSemVers = []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"2.0.0",
+		"2.1.0",
+		"2.1.1",
+	}
+
+	for i := 0; i+1 < len(orderedSemVers); i++ {
+		a, _ := ParseSemVer(orderedSemVers[i])
+		b, _ := ParseSemVer(orderedSemVers[i+1])
+		if !a.Less(b) || b.Less(a) {
+			t.Errorf("%s should be less than %s", a, b)
+		}
+	}
+
+	a, _ := ParseSemVer("1.0.0+build.1")
+	b, _ := ParseSemVer("1.0.0+build.2")
+	if !a.Equal(b) {
+		t.Errorf("%s should be equal to %s", a, b)
+	}
+}
+
+func TestSemVerToVersion(t *testing.T) {
+	var conversionCases = []struct {
+		semver   string
+		expected string
+		loss     Loss
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"1.2.3-rc.1+build.5", "1.2.3rc1+build.5", 0},
+		{"1.2.3-a.0", "1.2.3a0", 0},
+		{"1.2.3-rc1", "1.2.3rc1", LossPrerelease},
+		{"1.2.3-alpha.1", "1.2.3a1", LossPrerelease},
+		{"1.2.3-beta", "1.2.3b0", LossPrerelease},
+		{"1.2.3-rc.1.dev.2", "1.2.3rc1.dev2", LossPrerelease},
+		{"1.2.3-dev.4", "1.2.3.dev4", LossPrerelease},
+		{"1.2.3-x.7.z.92", "1.2.3.dev0", LossPrerelease},
+		{"1.2.3-rc.1.x", "1.2.3.dev0", LossPrerelease},
+		{"1.2.3+Build-5", "1.2.3+build.5", LossBuild},
+		{"1.2.3+-x", "1.2.3", LossBuild},
+		{"1.2.3-alpha+-x", "1.2.3a0", LossPrerelease | LossBuild},
+	}
+
+	for _, c := range conversionCases {
+		t.Run(c.semver, func(t *testing.T) {
+			s, err := ParseSemVer(c.semver)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			v, loss := s.Version()
+			if v.Complete() != c.expected || loss != c.loss {
+				t.Errorf("%s(%s)(actual) != %s(%s)(expected)", v.Complete(), loss, c.expected, c.loss)
+			}
+		})
+	}
+}
+
+func TestVersionToSemVer(t *testing.T) {
+	var conversionCases = []struct {
+		version  string
+		expected string
+		loss     Loss
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"1.2", "1.2.0", 0},
+		{"1.2.3.0", "1.2.3", 0},
+		{"1.2.3rc1+build.5", "1.2.3-rc.1+build.5", 0},
+		{"1.2.3.4", "1.2.3", LossRelease},
+		{"1!1.2.3", "1.2.3", LossEpoch},
+		{"1.2.3.post1", "1.2.3", LossPost},
+		{"1.2.3a1.dev2", "1.2.3-a.1", LossDev},
+		{"1!1.2.3.4b1.post1.dev1+ubuntu", "1.2.3-b.1+ubuntu", LossEpoch | LossRelease | LossPost | LossDev},
+	}
+
+	for _, c := range conversionCases {
+		t.Run(c.version, func(t *testing.T) {
+			v, err := ParseVersion(c.version)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			s, loss := v.SemVer()
+			if s.Complete() != c.expected || loss != c.loss {
+				t.Errorf("%s(%s)(actual) != %s(%s)(expected)", s.Complete(), loss, c.expected, c.loss)
+			}
+			if _, err := ParseSemVer(s.Complete()); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+
+	if loss := LossEpoch | LossBuild; loss.String() != "epoch|build" {
+		t.Errorf("%s(actual) != epoch|build(expected)", loss)
+	}
+}
+
+func TestConversionOrder(t *testing.T) {
+	// build metadata doesn't take part in the precedence of semver, so it's left out
+	semvers := []string{"1.2.3-a.0", "1.2.3-a.1", "1.2.3-alpha.2", "1.2.3-b.0", "1.2.3-dev.4", "1.2.3-rc.1",
+		"1.2.3-rc.1.dev.2", "1.2.3-x.7", "1.2.3", "1.2.4-rc.1", "1.2.4", "2.0.0"}
+	for _, a := range semvers {
+		for _, b := range semvers {
+			sa, _ := ParseSemVer(a)
+			sb, _ := ParseSemVer(b)
+			va, la := sa.Version()
+			vb, lb := sb.Version()
+			if la == 0 && lb == 0 && sa.Compare(sb) != va.Compare(vb) {
+				t.Errorf("%s vs %s: %d(actual) != %d(expected)", va.Complete(), vb.Complete(), va.Compare(vb), sa.Compare(sb))
+			}
+		}
+	}
+
+	versions := []string{"1.0.dev1", "1.0a1.dev1", "1.0a1", "1.0b2", "1.0rc1", "1.0", "1.0.post1", "1.0.1", "1.0.1.0", "1!0.5"}
+	for _, a := range versions {
+		for _, b := range versions {
+			va, _ := ParseVersion(a)
+			vb, _ := ParseVersion(b)
+			sa, la := va.SemVer()
+			sb, lb := vb.SemVer()
+			if la == 0 && lb == 0 && va.Compare(vb) != sa.Compare(sb) {
+				t.Errorf("%s vs %s: %d(actual) != %d(expected)", sa.Complete(), sb.Complete(), sa.Compare(sb), va.Compare(vb))
+			}
+		}
+	}
+}
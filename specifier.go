@@ -0,0 +1,371 @@
+package version
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// pep-440: https://peps.python.org/pep-0440/#version-specifiers
+const (
+	OpCompatible   = "~="
+	OpEqual        = "=="
+	OpNotEqual     = "!="
+	OpLessEqual    = "<="
+	OpGreaterEqual = ">="
+	OpLess         = "<"
+	OpGreater      = ">"
+	OpArbitrary    = "==="
+)
+
+// Specifier is a version clause made up of a comparison operator and a version, e.g. '>=1.0' or
+// '==1.2.*'.
+type Specifier struct {
+	operator string
+	version  string
+	wildcard bool
+
+	// spec is the parsed version, it's nil for the arbitrary equality.
+	spec *Version
+	// [lower, upper) is the range of prefix matching for wildcards and compatible release.
+	lower *Version
+	upper *Version
+}
+
+// ParseSpecifier parses a single version specifier with reference to packaging, an official pypi
+// packaging library https://github.com/pypa/packaging/blob/21.3/packaging/specifiers.py#L289.
+func ParseSpecifier(spec string) (*Specifier, error) {
+	match := specifierRe.FindStringSubmatch(spec)
+	if match == nil {
+		return nil, &SpecifierError{Specifier: spec, Kind: ErrInvalidSpecifier}
+	}
+
+	s := &Specifier{
+		operator: match[specifierRe.SubexpIndex("operator")],
+		version:  match[specifierRe.SubexpIndex("version")],
+	}
+
+	switch s.operator {
+	case OpArbitrary:
+		return s, nil
+	case OpEqual, OpNotEqual:
+		if !specifierEqualityRe.MatchString(s.version) {
+			return nil, &SpecifierError{Specifier: spec, Kind: ErrInvalidSpecifier}
+		}
+	case OpCompatible:
+		if !specifierCompatibleRe.MatchString(s.version) {
+			return nil, &SpecifierError{Specifier: spec, Kind: ErrInvalidSpecifier}
+		}
+	default:
+		if !specifierOrderedRe.MatchString(s.version) {
+			return nil, &SpecifierError{Specifier: spec, Kind: ErrInvalidSpecifier}
+		}
+	}
+
+	version := s.version
+	if strings.HasSuffix(version, ".*") {
+		s.wildcard = true
+		version = strings.TrimSuffix(version, ".*")
+	}
+	v, err := ParseVersion(version)
+	if err != nil {
+		return nil, &SpecifierError{Specifier: spec, Kind: ErrInvalidSpecifier, Err: err}
+	}
+	s.spec = v
+
+	if s.wildcard {
+		s.lower, s.upper = prefixRange(v)
+	} else if s.operator == OpCompatible {
+		// the suffix and the last release segment are ignored for prefix matching, which means
+		// '~=1.4.5a4' is equivalent to '>=1.4.5a4, ==1.4.*'
+		s.lower, s.upper = prefixRange(&Version{
+			epoch:   v.epoch,
+			release: v.release[:len(v.release)-1],
+		})
+	}
+
+	return s, nil
+}
+
+// prefixRange returns the range [lower, upper) of versions whose public version starts with the
+// prefix, e.g. [1.2.dev0, 1.3.dev0) for '1.2.*' and [1.0rc1.dev0, 1.0rc2.dev0) for '1.0rc1.*'.
+func prefixRange(prefix *Version) (*Version, *Version) {
+	lower := &Version{
+		epoch:   prefix.epoch,
+		release: prefix.release,
+		pre:     prefix.pre,
+		post:    prefix.post,
+		dev:     &segment{name: "dev", number: "0"},
+	}
+	upper := &Version{
+		epoch:   prefix.epoch,
+		release: prefix.release,
+		pre:     prefix.pre,
+		post:    prefix.post,
+		dev:     &segment{name: "dev", number: "0"},
+	}
+
+	switch {
+	case prefix.post != nil:
+		upper.post = &segment{name: prefix.post.name, number: prefix.post.number.inc()}
+	case prefix.pre != nil:
+		upper.pre = &segment{name: prefix.pre.name, number: prefix.pre.number.inc()}
+	default:
+		release := make([]number, len(prefix.release))
+		copy(release, prefix.release)
+		release[len(release)-1] = release[len(release)-1].inc()
+		upper.release = release
+	}
+
+	return lower, upper
+}
+
+func (s *Specifier) String() string {
+	return s.operator + s.version
+}
+
+// Operator returns the comparison operator such as '>='.
+func (s *Specifier) Operator() string {
+	return s.operator
+}
+
+// Version returns the version of specifier as it's written, including the wildcard suffix '.*'.
+func (s *Specifier) Version() string {
+	return s.version
+}
+
+// Contains reports whether a version satisfies the specifier by the rules of pep-440, pre-releases
+// are matched as any other version here, see SpecifierSet.Filter for pre-release admission. Only
+// the arbitrary equality can be satisfied by a LegacyVersion.
+func (s *Specifier) Contains(version IVersion) bool {
+	if s.operator == OpArbitrary {
+		return strings.EqualFold(version.Complete(), s.version)
+	}
+
+	v, ok := version.(*Version)
+	if !ok {
+		return false
+	}
+
+	switch s.operator {
+	case OpCompatible:
+		return comparePublic(v, s.spec) >= 0 && s.inPrefix(v)
+	case OpEqual:
+		return s.equal(v)
+	case OpNotEqual:
+		return !s.equal(v)
+	case OpLessEqual:
+		return comparePublic(v, s.spec) <= 0
+	case OpGreaterEqual:
+		return comparePublic(v, s.spec) >= 0
+	case OpLess:
+		if v.Compare(s.spec) >= 0 {
+			return false
+		}
+		// '<V' must not match a pre-release of V unless V itself is a pre-release
+		if !isPrerelease(s.spec) && isPrerelease(v) && baseEqual(v, s.spec) {
+			return false
+		}
+		return true
+	case OpGreater:
+		if v.Compare(s.spec) <= 0 {
+			return false
+		}
+		// '>V' must not match a post release of V unless V itself is a post release
+		if s.spec.post == nil && v.post != nil && baseEqual(v, s.spec) {
+			return false
+		}
+		// '>V' must not match a local version of V, which is technically greater than V
+		if len(v.local) != 0 && baseEqual(v, s.spec) {
+			return false
+		}
+		return true
+	}
+
+	return false
+}
+
+func (s *Specifier) equal(v *Version) bool {
+	if s.wildcard {
+		return s.inPrefix(v)
+	}
+	// local segment of candidate is ignored unless the specifier has one
+	if len(s.spec.local) == 0 {
+		return comparePublic(v, s.spec) == 0
+	}
+	return v.Compare(s.spec) == 0
+}
+
+func (s *Specifier) inPrefix(v *Version) bool {
+	return v.Compare(s.lower) >= 0 && v.Compare(s.upper) < 0
+}
+
+// baseEqual reports whether two versions have the same epoch and release.
+func baseEqual(a, b *Version) bool {
+	return a.epoch.compare(b.epoch) == 0 && compareRelease(a.release, b.release) == 0
+}
+
+// SpecifierSet is a set of specifiers joined by commas, a version satisfies the set only if it
+// satisfies all the specifiers.
+type SpecifierSet struct {
+	specs []*Specifier
+}
+
+// ParseSpecifierSet parses comma separated specifiers such as '>=1.0, !=1.3.*, <2', an empty
+// string results in an empty set which is satisfied by any version.
+func ParseSpecifierSet(specs string) (*SpecifierSet, error) {
+	set := new(SpecifierSet)
+	for _, spec := range strings.Split(specs, ",") {
+		if strings.TrimSpace(spec) == "" {
+			continue
+		}
+		s, err := ParseSpecifier(spec)
+		if err != nil {
+			return nil, err
+		}
+		set.specs = append(set.specs, s)
+	}
+
+	return set, nil
+}
+
+// NewSpecifierSet creates a SpecifierSet from parsed specifiers.
+func NewSpecifierSet(specs ...*Specifier) *SpecifierSet {
+	return &SpecifierSet{
+		specs: append([]*Specifier(nil), specs...),
+	}
+}
+
+// String returns the specifiers sorted and joined by commas, the same as packaging does.
+func (s *SpecifierSet) String() string {
+	var specs []string
+	for _, spec := range s.specs {
+		specs = append(specs, spec.String())
+	}
+	sort.Strings(specs)
+
+	return strings.Join(specs, ",")
+}
+
+func (s *SpecifierSet) Specifiers() []*Specifier {
+	return append([]*Specifier(nil), s.specs...)
+}
+
+// Contains reports whether a version satisfies all the specifiers of set.
+func (s *SpecifierSet) Contains(version IVersion) bool {
+	for _, spec := range s.specs {
+		if !spec.Contains(version) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Prereleases reports whether the specifier explicitly mentions a pre-release with an inclusive
+// operator, e.g. '>=1.0rc1' or '==2.0b1.*', which means the user is asking for pre-releases.
+func (s *Specifier) Prereleases() bool {
+	switch s.operator {
+	case OpEqual, OpGreaterEqual, OpLessEqual, OpCompatible:
+		return isPrerelease(s.spec)
+	case OpArbitrary:
+		v, err := ParseVersion(s.version)
+		return err == nil && isPrerelease(v)
+	default:
+		return false
+	}
+}
+
+// Prereleases reports whether any specifier of set explicitly mentions a pre-release.
+func (s *SpecifierSet) Prereleases() bool {
+	for _, spec := range s.specs {
+		if spec.Prereleases() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PrereleasePolicy decides when SpecifierSet.Filter admits pre-releases (including dev releases),
+// the modes are named after those of uv https://docs.astral.sh/uv/concepts/resolution/#pre-releases.
+type PrereleasePolicy int
+
+const (
+	// PrereleaseIfNecessaryOrExplicit admits pre-releases if any specifier explicitly mentions a
+	// pre-release, or if no final release satisfies the set, which is the default recommended by
+	// pep-440 https://peps.python.org/pep-0440/#handling-of-pre-releases.
+	PrereleaseIfNecessaryOrExplicit PrereleasePolicy = iota
+	// PrereleaseDisallow never admits pre-releases.
+	PrereleaseDisallow
+	// PrereleaseAllow always admits pre-releases.
+	PrereleaseAllow
+	// PrereleaseIfNecessary admits pre-releases only if no final release satisfies the set.
+	PrereleaseIfNecessary
+	// PrereleaseExplicit admits pre-releases only if any specifier explicitly mentions one.
+	PrereleaseExplicit
+)
+
+func (p PrereleasePolicy) String() string {
+	switch p {
+	case PrereleaseIfNecessaryOrExplicit:
+		return "if-necessary-or-explicit"
+	case PrereleaseDisallow:
+		return "disallow"
+	case PrereleaseAllow:
+		return "allow"
+	case PrereleaseIfNecessary:
+		return "if-necessary"
+	case PrereleaseExplicit:
+		return "explicit"
+	default:
+		return fmt.Sprintf("PrereleasePolicy(%d)", int(p))
+	}
+}
+
+// FilterOptions controls the behaviour of SpecifierSet.Filter, the zero value is the default.
+type FilterOptions struct {
+	Prereleases PrereleasePolicy
+}
+
+// Filter returns versions satisfying the set in their original order, the pre-releases among them
+// are admitted according to the policy of opts. Like packaging, a LegacyVersion is filtered out
+// unless it's matched by an arbitrary equality.
+func (s *SpecifierSet) Filter(versions Versions, opts FilterOptions) Versions {
+	var matched Versions
+	var final bool
+	for _, v := range versions {
+		if _, ok := v.(*Version); !ok && len(s.specs) == 0 {
+			continue
+		}
+		if !s.Contains(v) {
+			continue
+		}
+		matched = append(matched, v)
+		final = final || !isPrerelease(v)
+	}
+
+	var admitted bool
+	switch opts.Prereleases {
+	case PrereleaseAllow:
+		admitted = true
+	case PrereleaseIfNecessary:
+		admitted = !final
+	case PrereleaseExplicit:
+		admitted = s.Prereleases()
+	case PrereleaseIfNecessaryOrExplicit:
+		admitted = !final || s.Prereleases()
+	}
+	if admitted {
+		return matched
+	}
+
+	filtered := matched[:0]
+	for _, v := range matched {
+		if !isPrerelease(v) {
+			filtered = append(filtered, v)
+		}
+	}
+
+	return filtered
+}
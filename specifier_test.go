@@ -0,0 +1,380 @@
+package version
+
+import (
+	"errors"
+	"testing"
+)
+
+// the test cases come from https://github.com/pypa/packaging/blob/21.3/tests/test_specifiers.py
+
+func TestValidSpecifier(t *testing.T) {
+	var validSpecifiers = []string{
+		// Operator-less specifier are not allowed
+		"~=2.0",
+		"==2.1.*",
+		"==2.1.0.3",
+		"!=2.2.*",
+		"!=2.2.0.5",
+		"<=5",
+		">=7.9a1",
+		"<1.0.dev1",
+		">2.0.post1",
+		"===lolwat",
+		// Local version are allowed with equality
+		"==1.0+5",
+		"!=1.0+deadbeef",
+		// Whitespace is allowed around the operator and version
+		" == 1.0 ",
+		"~= 2.2",
+		// Case insensitive
+		"==1.0RC1",
+		">=V2.0",
+	}
+
+	for _, spec := range validSpecifiers {
+		if _, err := ParseSpecifier(spec); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestInvalidSpecifier(t *testing.T) {
+	var invalidSpecifiers = []string{
+		// Operator-less specifier
+		"2.0",
+		// Invalid operator
+		"=>2.0",
+		// Version-less specifier
+		"==",
+		// Local segment on operators which don't support them
+		"~=1.0+5",
+		">=1.0+deadbeef",
+		"<=1.0+abc123",
+		">1.0+watwat",
+		"<1.0+1.0",
+		// Prefix matching on operators which don't support them
+		"~=1.0.*",
+		">=1.0.*",
+		"<=1.0.*",
+		">1.0.*",
+		"<1.0.*",
+		// Combination of local and prefix matching on operators which do support one or the other
+		"==1.0.*+5",
+		"!=1.0.*+deadbeef",
+		// Prefix matching cannot be used inside of a local version
+		"==1.0+5.*",
+		"!=1.0+deadbeef.*",
+		// Prefix matching must appear at the end
+		"==1.0.*.5",
+		// Compatible operator requires 2 digits in the release operator
+		"~=1",
+		// Cannot use a prefix matching after a .devN version
+		"==1.0.dev1.*",
+		"!=1.0.dev1.*",
+	}
+
+	for _, spec := range invalidSpecifiers {
+		if _, err := ParseSpecifier(spec); !errors.Is(err, ErrInvalidSpecifier) {
+			t.Errorf("'%s' should be an invalid specifier, %v", spec, err)
+		}
+	}
+}
+
+func TestSpecifierContains(t *testing.T) {
+	var specifierCases = []struct {
+		version  string
+		spec     string
+		expected bool
+	}{
+		// Test the equality operation
+		{"2.0", "==2", true},
+		{"2.0", "==2.0", true},
+		{"2.0", "==2.0.0", true},
+		{"2.0+deadbeef", "==2", true},
+		{"2.0+deadbeef", "==2.0", true},
+		{"2.0+deadbeef", "==2.0.0", true},
+		{"2.0+deadbeef", "==2+deadbeef", true},
+		{"2.0+deadbeef", "==2.0+deadbeef", true},
+		{"2.0+deadbeef", "==2.0.0+deadbeef", true},
+		{"2.0+deadbeef.0", "==2.0.0+deadbeef.00", true},
+		{"2.1", "==2", false},
+		{"2.1", "==2.0", false},
+		{"2.1", "==2.0.0", false},
+		{"2.0", "==2.0+deadbeef", false},
+		// Test the equality operation with a prefix
+		{"2.dev1", "==2.*", true},
+		{"2a1", "==2.*", true},
+		{"2a1.post1", "==2.*", true},
+		{"2b1", "==2.*", true},
+		{"2b1.dev1", "==2.*", true},
+		{"2c1", "==2.*", true},
+		{"2c1.post1.dev1", "==2.*", true},
+		{"2rc1", "==2.*", true},
+		{"2", "==2.*", true},
+		{"2.0", "==2.*", true},
+		{"2.0.0", "==2.*", true},
+		{"2.0.post1", "==2.0.post1.*", true},
+		{"2.0.post1.dev1", "==2.0.post1.*", true},
+		{"2.1+local.version", "==2.1.*", true},
+		{"2.0", "==3.*", false},
+		{"2.1", "==2.0.*", false},
+		{"1.0rc1.post2", "==1.0rc1.*", true},
+		{"1.0rc2", "==1.0rc1.*", false},
+		// Test the in-equality operation
+		{"2.1", "!=2", true},
+		{"2.1", "!=2.0", true},
+		{"2.0.1", "!=2", true},
+		{"2.0.1", "!=2.0", true},
+		{"2.0.1", "!=2.0.0", true},
+		{"2.0", "!=2.0+deadbeef", true},
+		{"2.0", "!=2", false},
+		{"2.0", "!=2.0", false},
+		{"2.0", "!=2.0.0", false},
+		{"2.0+deadbeef", "!=2", false},
+		{"2.0+deadbeef", "!=2.0", false},
+		{"2.0+deadbeef", "!=2.0.0", false},
+		{"2.0+deadbeef", "!=2+deadbeef", false},
+		{"2.0+deadbeef", "!=2.0+deadbeef", false},
+		{"2.0+deadbeef", "!=2.0.0+deadbeef", false},
+		{"2.0+deadbeef.0", "!=2.0.0+deadbeef.00", false},
+		// Test the in-equality operation with a prefix
+		{"2.0", "!=3.*", true},
+		{"2.1", "!=2.0.*", true},
+		{"2.dev1", "!=2.*", false},
+		{"2a1", "!=2.*", false},
+		{"2a1.post1", "!=2.*", false},
+		{"2b1", "!=2.*", false},
+		{"2b1.dev1", "!=2.*", false},
+		{"2c1", "!=2.*", false},
+		{"2c1.post1.dev1", "!=2.*", false},
+		{"2rc1", "!=2.*", false},
+		{"2", "!=2.*", false},
+		{"2.0", "!=2.*", false},
+		{"2.0.0", "!=2.*", false},
+		{"2.0.post1", "!=2.0.post1.*", false},
+		{"2.0.post1.dev1", "!=2.0.post1.*", false},
+		// Test the greater than equal operation
+		{"2.0", ">=2", true},
+		{"2.0", ">=2.0", true},
+		{"2.0", ">=2.0.0", true},
+		{"2.0.post1", ">=2", true},
+		{"2.0.post1.dev1", ">=2", true},
+		{"3", ">=2", true},
+		{"2.0.dev1", ">=2", false},
+		{"2.0a1", ">=2", false},
+		{"2.0a1.dev1", ">=2", false},
+		{"2.0b1", ">=2", false},
+		{"2.0b1.post1", ">=2", false},
+		{"2.0c1", ">=2", false},
+		{"2.0c1.post1.dev1", ">=2", false},
+		{"2.0rc1", ">=2", false},
+		{"1", ">=2", false},
+		// Test the less than equal operation
+		{"2.0", "<=2", true},
+		{"2.0", "<=2.0", true},
+		{"2.0", "<=2.0.0", true},
+		{"2.0.dev1", "<=2", true},
+		{"2.0a1", "<=2", true},
+		{"2.0a1.dev1", "<=2", true},
+		{"2.0b1", "<=2", true},
+		{"2.0b1.post1", "<=2", true},
+		{"2.0c1", "<=2", true},
+		{"2.0c1.post1.dev1", "<=2", true},
+		{"2.0rc1", "<=2", true},
+		{"1", "<=2", true},
+		{"2.0+local", "<=2", true},
+		{"2.0.post1", "<=2", false},
+		{"2.0.post1.dev1", "<=2", false},
+		{"3", "<=2", false},
+		// Test the greater than operation
+		{"3", ">2", true},
+		{"2.1", ">2.0", true},
+		{"2.0.1", ">2", true},
+		{"2.1.post1", ">2", true},
+		{"2.1+local.version", ">2", true},
+		{"1", ">2", false},
+		{"2.0.dev1", ">2", false},
+		{"2.0a1", ">2", false},
+		{"2.0a1.post1", ">2", false},
+		{"2.0b1", ">2", false},
+		{"2.0b1.post1", ">2", false},
+		{"2.0c1", ">2", false},
+		{"2.0c1.post1.dev1", ">2", false},
+		{"2.0rc1", ">2", false},
+		{"2.0", ">2", false},
+		{"2.0.post1", ">2", false},
+		{"2.0.post1.dev1", ">2", false},
+		{"2.0+local.version", ">2", false},
+		{"2.0.post2", ">2.0.post1", true},
+		// Test the less than operation
+		{"1", "<2", true},
+		{"2.0", "<2.1", true},
+		{"2.0.dev0", "<2.1", true},
+		{"2.0.dev1", "<2", false},
+		{"2.0a1", "<2", false},
+		{"2.0a1.post1", "<2", false},
+		{"2.0b1", "<2", false},
+		{"2.0b2.dev1", "<2", false},
+		{"2.0c1", "<2", false},
+		{"2.0c1.post1.dev1", "<2", false},
+		{"2.0rc1", "<2", false},
+		{"2.0", "<2", false},
+		{"2.post1", "<2", false},
+		{"2.post1.dev1", "<2", false},
+		{"3", "<2", false},
+		{"2.0a1", "<2.0b1", true},
+		// Test the compatibility operation
+		{"1", "~=1.0", true},
+		{"1.0.1", "~=1.0", true},
+		{"1.1", "~=1.0", true},
+		{"1.9999999", "~=1.0", true},
+		{"1.1", "~=1.0a1", true},
+		{"1.4.9", "~=1.4.5a4", true},
+		{"1.5", "~=1.4.5a4", false},
+		{"2.0", "~=1.0", false},
+		{"1.1.0", "~=1.0.0", false},
+		{"1.1.post1", "~=1.0.0", false},
+		// Test that epochs are handled sanely
+		{"2!1.0", "~=2!1.0", true},
+		{"2!1.0", "==2!1.*", true},
+		{"2!1.0", "==2!1.0", true},
+		{"2!1.0", "!=1.0", true},
+		{"1.0", "!=2!1.0", true},
+		{"1.0", "<=2!0.1", true},
+		{"2!1.0", ">=2.0", true},
+		{"1.0", "<2!0.1", true},
+		{"2!1.0", ">2.0", true},
+		{"1.0", "~=2!1.0", false},
+		{"2!1.0", "~=1.0", false},
+		{"2!1.0", "==1.0", false},
+		{"1.0", "==2!1.0", false},
+		{"2!1.0", "==1.*", false},
+		{"1.0", "==2!1.*", false},
+		{"2!1.0", "!=2!1.0", false},
+		// Test some normalization rules
+		{"2.0.5", ">2.0dev", true},
+		// Test the arbitrary equality
+		{"1.0", "===1.0", true},
+		{"1.0", "===1.0.0", false},
+		{"foobar", "===FooBar", true},
+		{"foobar", "==1.0", false},
+	}
+
+	for _, c := range specifierCases {
+		t.Run(c.version+c.spec, func(t *testing.T) {
+			spec, err := ParseSpecifier(c.spec)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			v, err := Parse(c.version)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if spec.Contains(v) != c.expected {
+				t.Errorf("%s in %s, %t(expected)", c.version, c.spec, c.expected)
+			}
+		})
+	}
+}
+
+func TestSpecifierSet(t *testing.T) {
+	var specifierSetCases = []struct {
+		specs    string
+		str      string
+		version  string
+		expected bool
+	}{
+		{"", "", "1.0", true},
+		{"", "", "foobar", true},
+		{">=1.0,<2", "<2,>=1.0", "1.5", true},
+		{" >=1.0 , <2 ", "<2,>=1.0", "2.0", false},
+		{">=1.4,<2,!=1.5.*", "!=1.5.*,<2,>=1.4", "1.5.3", false},
+		{">=1.4,<2,!=1.5.*", "!=1.5.*,<2,>=1.4", "1.6", true},
+		{"~=1.6,>=1.4,", ">=1.4,~=1.6", "1.6.1", true},
+	}
+
+	for _, c := range specifierSetCases {
+		t.Run(c.specs, func(t *testing.T) {
+			set, err := ParseSpecifierSet(c.specs)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if set.String() != c.str {
+				t.Errorf("%s(actual) != %s(expected)", set.String(), c.str)
+			}
+			v, err := Parse(c.version)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if set.Contains(v) != c.expected {
+				t.Errorf("%s in %s, %t(expected)", c.version, c.specs, c.expected)
+			}
+		})
+	}
+
+	if _, err := ParseSpecifierSet(">=1.0,=>2"); err == nil {
+		t.Error("'>=1.0,=>2' should be an invalid specifier set")
+	}
+}
+
+func TestSpecifierSetFilter(t *testing.T) {
+	var filterCases = []struct {
+		specs    string
+		policy   PrereleasePolicy
+		versions []string
+		expected []string
+	}{
+		// pre-releases are admitted only if necessary or explicitly mentioned by default
+		{"", PrereleaseIfNecessaryOrExplicit, []string{"1.0", "2.0a1"}, []string{"1.0"}},
+		{"", PrereleaseIfNecessaryOrExplicit, []string{"1.0a1", "2.0a1"}, []string{"1.0a1", "2.0a1"}},
+		{">=1.0", PrereleaseIfNecessaryOrExplicit, []string{"1.0", "2.0a1", "2.0.dev1"}, []string{"1.0"}},
+		{">=1.0", PrereleaseIfNecessaryOrExplicit, []string{"0.9", "2.0a1", "2.0.dev1"}, []string{"2.0a1", "2.0.dev1"}},
+		{">=1.0rc1", PrereleaseIfNecessaryOrExplicit, []string{"1.0rc1", "1.0", "2.0a1"}, []string{"1.0rc1", "1.0", "2.0a1"}},
+		{"==2.0b1.*", PrereleaseIfNecessaryOrExplicit, []string{"2.0b1", "2.0b1.post1", "2.0"}, []string{"2.0b1", "2.0b1.post1"}},
+		// exclusive comparisons with pre-releases are not explicit
+		{"<1.0rc1", PrereleaseIfNecessaryOrExplicit, []string{"0.9", "1.0b1"}, []string{"0.9"}},
+		{"<1.0rc1", PrereleaseExplicit, []string{"1.0b1"}, nil},
+		{">=1.0", PrereleaseDisallow, []string{"0.9", "2.0a1", "2.0.dev1"}, nil},
+		{">=1.0", PrereleaseAllow, []string{"1.0", "2.0a1"}, []string{"1.0", "2.0a1"}},
+		{">=1.0", PrereleaseIfNecessary, []string{"1.0", "2.0a1"}, []string{"1.0"}},
+		{">=1.0", PrereleaseIfNecessary, []string{"0.9", "2.0a1"}, []string{"2.0a1"}},
+		{">=1.0rc1", PrereleaseIfNecessary, []string{"1.0", "2.0a1"}, []string{"1.0"}},
+		{">=1.0rc1", PrereleaseExplicit, []string{"1.0", "2.0a1"}, []string{"1.0", "2.0a1"}},
+		// post releases and local versions are not pre-releases
+		{">=1.0", PrereleaseDisallow, []string{"1.0.post1", "1.0+local"}, []string{"1.0.post1", "1.0+local"}},
+		// legacy versions are filtered out unless matched by arbitrary equality
+		{"", PrereleaseAllow, []string{"1.0-x", "1.0"}, []string{"1.0"}},
+		{"===1.0-x", PrereleaseAllow, []string{"1.0-x", "1.0"}, []string{"1.0-x"}},
+	}
+
+	for _, c := range filterCases {
+		t.Run(c.specs+"/"+c.policy.String(), func(t *testing.T) {
+			set, err := ParseSpecifierSet(c.specs)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			versions, err := ParseVersions(c.versions)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			actual := completes(set.Filter(versions, FilterOptions{Prereleases: c.policy}))
+			if len(actual) != len(c.expected) {
+				t.Errorf("%v(actual) != %v(expected)", actual, c.expected)
+				return
+			}
+			for i := range actual {
+				if actual[i] != c.expected[i] {
+					t.Errorf("%v(actual) != %v(expected)", actual, c.expected)
+					return
+				}
+			}
+		})
+	}
+}
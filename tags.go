@@ -0,0 +1,220 @@
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Tag is a compatibility tag of wheels, for details:
+// https://packaging.python.org/en/latest/specifications/platform-compatibility-tags/.
+type Tag struct {
+	Interpreter string `json:"interpreter"`
+	ABI         string `json:"abi"`
+	Platform    string `json:"platform"`
+}
+
+func (t Tag) String() string {
+	return t.Interpreter + "-" + t.ABI + "-" + t.Platform
+}
+
+// ParseTag parses a compressed tag set such as 'py2.py3-none-any' into the tags it represents, the
+// tags are lower cased as packaging does.
+func ParseTag(tag string) ([]Tag, error) {
+	parts := strings.Split(strings.ToLower(tag), "-")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return nil, fmt.Errorf("invalid tag '%s'", tag)
+	}
+
+	return expandTags(strings.Split(parts[0], "."), strings.Split(parts[1], "."), strings.Split(parts[2], ".")), nil
+}
+
+func expandTags(interpreters, abis, platforms []string) []Tag {
+	var tags []Tag
+	for _, interpreter := range interpreters {
+		for _, abi := range abis {
+			for _, platform := range platforms {
+				tags = append(tags, Tag{
+					Interpreter: strings.ToLower(interpreter),
+					ABI:         strings.ToLower(abi),
+					Platform:    strings.ToLower(platform),
+				})
+			}
+		}
+	}
+
+	return tags
+}
+
+// Tags returns the tags of wheel expanded from its compressed tag sets.
+func (w *Wheel) Tags() []Tag {
+	return expandTags(w.Pyvers, w.Abis, w.Plats)
+}
+
+// SupportIndex returns the index of the most preferred tag in supported which the wheel is compatible
+// with, or -1 if it's not compatible, a lower index means a better match. The same as pip's
+// support_index_min: https://github.com/pypa/pip/blob/23.0.1/src/pip/_internal/models/wheel.py#L69.
+func (w *Wheel) SupportIndex(supported []Tag) int {
+	tags := make(map[Tag]bool)
+	for _, tag := range w.Tags() {
+		tags[tag] = true
+	}
+	for i, tag := range supported {
+		if tags[tag] {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// IsSupported reports whether the wheel is compatible with any of supported.
+func (w *Wheel) IsSupported(supported []Tag) bool {
+	return w.SupportIndex(supported) >= 0
+}
+
+// cpythonTags returns the tags supported by cpython in order of preference, see
+// https://github.com/pypa/packaging/blob/23.0/src/packaging/tags.py#L169.
+func cpythonTags(major, minor int, platforms []string) []Tag {
+	interpreter := fmt.Sprintf("cp%d%d", major, minor)
+	tags := expandTags([]string{interpreter}, []string{interpreter}, platforms)
+
+	// abi3 applies since python 3.2
+	abi3 := major > 3 || (major == 3 && minor >= 2)
+	if abi3 {
+		tags = append(tags, expandTags([]string{interpreter}, []string{"abi3"}, platforms)...)
+	}
+	tags = append(tags, expandTags([]string{interpreter}, []string{"none"}, platforms)...)
+	if abi3 {
+		for m := minor - 1; m > 1; m-- {
+			tags = append(tags, expandTags([]string{fmt.Sprintf("cp%d%d", major, m)}, []string{"abi3"}, platforms)...)
+		}
+	}
+
+	return append(tags, compatibleTags(major, minor, interpreter, platforms)...)
+}
+
+// genericTags returns the tags supported by other interpreters in order of preference, see
+// https://github.com/pypa/packaging/blob/23.0/src/packaging/tags.py#L228.
+func genericTags(major, minor int, interpreter, abi string, platforms []string) []Tag {
+	tags := expandTags([]string{interpreter}, []string{abi, "none"}, platforms)
+	return append(tags, compatibleTags(major, minor, interpreter, platforms)...)
+}
+
+// compatibleTags returns the tags of pure python wheels, see
+// https://github.com/pypa/packaging/blob/23.0/src/packaging/tags.py#L287.
+func compatibleTags(major, minor int, interpreter string, platforms []string) []Tag {
+	var versions []string
+	versions = append(versions, fmt.Sprintf("py%d%d", major, minor), fmt.Sprintf("py%d", major))
+	for m := minor - 1; m >= 0; m-- {
+		versions = append(versions, fmt.Sprintf("py%d%d", major, m))
+	}
+
+	tags := expandTags(versions, []string{"none"}, platforms)
+	tags = append(tags, Tag{Interpreter: interpreter, ABI: "none", Platform: "any"})
+	return append(tags, expandTags(versions, []string{"none"}, []string{"any"})...)
+}
+
+// legacyManylinux is the aliases of manylinux tags before pep-600.
+var legacyManylinux = map[[2]int]string{
+	{2, 17}: "manylinux2014",
+	{2, 12}: "manylinux2010",
+	{2, 5}:  "manylinux1",
+}
+
+// manylinuxTags returns the platform tags supported by glibc of version 2.minor, see
+// https://github.com/pypa/packaging/blob/23.0/src/packaging/_manylinux.py#L205.
+func manylinuxTags(minor int, arch string) []string {
+	// glibc 2.17 is the oldest version supported by architectures other than x86
+	oldest := 17
+	if arch == "x86_64" || arch == "i686" {
+		oldest = 5
+	}
+
+	var platforms []string
+	for m := minor; m >= oldest; m-- {
+		platforms = append(platforms, fmt.Sprintf("manylinux_2_%d_%s", m, arch))
+		if legacy, ok := legacyManylinux[[2]int{2, m}]; ok {
+			if legacy == "manylinux2014" || arch == "x86_64" || arch == "i686" {
+				platforms = append(platforms, legacy+"_"+arch)
+			}
+		}
+	}
+
+	return append(platforms, "linux_"+arch)
+}
+
+// musllinuxTags returns the platform tags supported by musl of version major.minor, see
+// https://github.com/pypa/packaging/blob/23.0/src/packaging/_musllinux.py#L56.
+func musllinuxTags(major, minor int, arch string) []string {
+	var platforms []string
+	for m := minor; m >= 0; m-- {
+		platforms = append(platforms, fmt.Sprintf("musllinux_%d_%d_%s", major, m, arch))
+	}
+
+	return append(platforms, "linux_"+arch)
+}
+
+// macosTags returns the platform tags supported by macOS of version major.minor, see
+// https://github.com/pypa/packaging/blob/23.0/src/packaging/tags.py#L369.
+func macosTags(major, minor int, arch string) []string {
+	var platforms []string
+	if major == 10 {
+		for m := minor; m >= 0; m-- {
+			for _, format := range macosBinaryFormats(10, m, arch) {
+				platforms = append(platforms, fmt.Sprintf("macosx_10_%d_%s", m, format))
+			}
+		}
+		return platforms
+	}
+
+	for v := major; v > 10; v-- {
+		for _, format := range macosBinaryFormats(v, 0, arch) {
+			platforms = append(platforms, fmt.Sprintf("macosx_%d_0_%s", v, format))
+		}
+	}
+	// binaries built for 10.x run on 11+, arm64 ones only if they are universal2
+	for m := 16; m > 3; m-- {
+		if arch == "x86_64" {
+			for _, format := range macosBinaryFormats(10, m, arch) {
+				platforms = append(platforms, fmt.Sprintf("macosx_10_%d_%s", m, format))
+			}
+		} else {
+			platforms = append(platforms, fmt.Sprintf("macosx_10_%d_universal2", m))
+		}
+	}
+
+	return platforms
+}
+
+func macosBinaryFormats(major, minor int, arch string) []string {
+	formats := []string{arch}
+	if arch == "x86_64" {
+		if major == 10 && minor < 4 {
+			return nil
+		}
+		formats = append(formats, "intel", "fat64", "fat32")
+	}
+	if arch == "arm64" || arch == "x86_64" {
+		formats = append(formats, "universal2")
+	}
+	if arch == "x86_64" {
+		formats = append(formats, "universal")
+	}
+
+	return formats
+}
+
+// parseVersionPair parses a version like '2_17' of platform tags.
+func parseVersionPair(major, minor string) (int, int, error) {
+	x, err := strconv.Atoi(major)
+	if err != nil {
+		return 0, 0, err
+	}
+	y, err := strconv.Atoi(minor)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return x, y, nil
+}
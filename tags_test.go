@@ -0,0 +1,126 @@
+package version
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTag(t *testing.T) {
+	var tagCases = []struct {
+		tag      string
+		expected string
+	}{
+		{"py3-none-any", "py3-none-any"},
+		{"py2.py3-none-any", "py2-none-any py3-none-any"},
+		{"CP38-cp38-manylinux_2_17_x86_64.manylinux2014_x86_64", "cp38-cp38-manylinux_2_17_x86_64 cp38-cp38-manylinux2014_x86_64"},
+		{"cp37-abi3.none-win32", "cp37-abi3-win32 cp37-none-win32"},
+	}
+
+	for _, c := range tagCases {
+		t.Run(c.tag, func(t *testing.T) {
+			tags, err := ParseTag(c.tag)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if actual := joinTags(tags); actual != c.expected {
+				t.Errorf("%s(actual) != %s(expected)", actual, c.expected)
+			}
+		})
+	}
+
+	for _, tag := range []string{"", "py3-none", "py3--any", "py3-none-any-x"} {
+		if _, err := ParseTag(tag); err == nil {
+			t.Errorf("%s should be an invalid tag", tag)
+		}
+	}
+}
+
+func TestCpythonTags(t *testing.T) {
+	tags := cpythonTags(3, 12, manylinuxTags(17, "aarch64"))
+	if len(tags) != 96 {
+		t.Errorf("%d(actual) != 96(expected)", len(tags))
+	}
+
+	expected := "cp312-cp312-manylinux_2_17_aarch64 cp312-cp312-manylinux2014_aarch64 cp312-cp312-linux_aarch64 " +
+		"cp312-abi3-manylinux_2_17_aarch64"
+	if actual := joinTags(tags[:4]); actual != expected {
+		t.Errorf("%s(actual) != %s(expected)", actual, expected)
+	}
+	expected = "cp312-none-any py312-none-any py3-none-any py311-none-any"
+	if actual := joinTags(tags[81:85]); actual != expected {
+		t.Errorf("%s(actual) != %s(expected)", actual, expected)
+	}
+	if actual := tags[len(tags)-1].String(); actual != "py30-none-any" {
+		t.Errorf("%s(actual) != py30-none-any(expected)", actual)
+	}
+}
+
+func TestPlatformTags(t *testing.T) {
+	var platformCases = []struct {
+		platforms []string
+		expected  string
+	}{
+		{manylinuxTags(17, "aarch64"), "manylinux_2_17_aarch64 manylinux2014_aarch64 linux_aarch64"},
+		{manylinuxTags(12, "x86_64")[:3], "manylinux_2_12_x86_64 manylinux2010_x86_64 manylinux_2_11_x86_64"},
+		{manylinuxTags(12, "x86_64")[8:], "manylinux_2_5_x86_64 manylinux1_x86_64 linux_x86_64"},
+		{musllinuxTags(1, 2, "x86_64"), "musllinux_1_2_x86_64 musllinux_1_1_x86_64 musllinux_1_0_x86_64 linux_x86_64"},
+		{macosTags(10, 5, "x86_64")[:7], "macosx_10_5_x86_64 macosx_10_5_intel macosx_10_5_fat64 macosx_10_5_fat32 " +
+			"macosx_10_5_universal2 macosx_10_5_universal macosx_10_4_x86_64"},
+		{macosTags(12, 0, "arm64")[:5], "macosx_12_0_arm64 macosx_12_0_universal2 macosx_11_0_arm64 macosx_11_0_universal2 " +
+			"macosx_10_16_universal2"},
+	}
+
+	for _, c := range platformCases {
+		if actual := strings.Join(c.platforms, " "); actual != c.expected {
+			t.Errorf("%s(actual) != %s(expected)", actual, c.expected)
+		}
+	}
+
+	if n := len(macosTags(11, 0, "arm64")); n != 15 {
+		t.Errorf("%d(actual) != 15(expected)", n)
+	}
+	if n := len(macosTags(10, 3, "x86_64")); n != 0 {
+		t.Errorf("%d(actual) != 0(expected)", n)
+	}
+}
+
+func TestWheelSupportIndex(t *testing.T) {
+	supported := cpythonTags(3, 8, manylinuxTags(17, "x86_64"))
+
+	var wheelCases = []struct {
+		filename string
+		expected int
+	}{
+		{"numpy-1.24.2-cp38-cp38-manylinux_2_17_x86_64.manylinux2014_x86_64.whl", 0},
+		{"cryptography-39.0.1-cp36-abi3-manylinux_2_17_x86_64.manylinux2014_x86_64.whl", 68},
+		{"six-1.16.0-py2.py3-none-any.whl", 325},
+		{"numpy-1.24.2-cp38-cp38-win_amd64.whl", -1},
+		{"numpy-1.24.2-cp39-cp39-manylinux_2_17_x86_64.whl", -1},
+	}
+
+	for _, c := range wheelCases {
+		t.Run(c.filename, func(t *testing.T) {
+			whl, err := NewWheel(c.filename)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if actual := whl.SupportIndex(supported); actual != c.expected {
+				t.Errorf("%d(actual) != %d(expected)", actual, c.expected)
+			}
+			if whl.IsSupported(supported) != (c.expected >= 0) {
+				t.Errorf("%v(actual) != %v(expected)", whl.IsSupported(supported), c.expected >= 0)
+			}
+		})
+	}
+}
+
+func joinTags(tags []Tag) string {
+	var s []string
+	for _, tag := range tags {
+		s = append(s, tag.String())
+	}
+
+	return strings.Join(s, " ")
+}
@@ -2,11 +2,14 @@ package version
 
 import (
 	"fmt"
+	"math/big"
 	"regexp"
 	"strconv"
 	"strings"
 )
 
+// Stage is a pre, post or dev segment of a version, its Number is math.MaxInt64 if the number of
+// the segment overflows int64, see Version.BigPre, BigPost and BigDev for the exact one.
 type Stage struct {
 	Name   string
 	Number int64
@@ -16,9 +19,12 @@ func (t *Stage) String() string {
 	return t.Name + strconv.FormatInt(t.Number, 10)
 }
 
-// IVersion .
-// TODO version comparable
+// IVersion is the common behaviour of Version and LegacyVersion, versions of either kind are
+// totally ordered by Compare, so callers never need to know which one Parse returned.
 type IVersion interface {
+	Compare(other IVersion) int
+	Less(other IVersion) bool
+	Equal(other IVersion) bool
 	Complete() string
 	Public() string
 	Base() string
@@ -31,39 +37,42 @@ type IVersion interface {
 }
 
 type Version struct {
-	epoch   int64
-	release []int64
-	dev     *Stage
-	pre     *Stage
-	post    *Stage
+	epoch   number
+	release []number
+	dev     *segment
+	pre     *segment
+	post    *segment
 	local   []string
 }
 
 // ParseVersion implements a standard version parser with reference to packaging, an official
 // pypi packaging library https://github.com/pypa/packaging/blob/21.3/packaging/version.py#L257.
 func ParseVersion(version string) (*Version, error) {
+	v, component, ok := parseVersion(version)
+	if !ok {
+		return nil, &VersionError{Version: version, Component: component, Kind: ErrInvalidVersion}
+	}
+
+	return v, nil
+}
+
+// parseVersionRegexp is the regexp implementation of ParseVersion, which is much slower than the
+// hand-written parser, it's kept as the reference of the latter.
+func parseVersionRegexp(version string) (*Version, error) {
 	match := irregularVersionRe.FindStringSubmatch(version)
 	if match == nil {
-		return nil, fmt.Errorf("not a valid version '%s'", version)
+		return nil, &VersionError{Version: version, Kind: ErrInvalidVersion}
 	}
 
-	stdVersion := new(Version)
+	stdVersion := &Version{epoch: "0"}
 	if epoch := match[irregularVersionRe.SubexpIndex("epoch")]; epoch != "" {
-		e, err := strconv.ParseInt(epoch, 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("not a valid epoch, %s", err.Error())
-		}
-		stdVersion.epoch = e
+		stdVersion.epoch = parseNumber(epoch)
 	}
 
 	if release := match[irregularVersionRe.SubexpIndex("release")]; release != "" {
-		var rel []int64
+		var rel []number
 		for _, r := range strings.Split(release, ".") {
-			digit, err := strconv.ParseInt(r, 10, 64)
-			if err != nil {
-				return nil, fmt.Errorf("not a valid release version, %s", err.Error())
-			}
-			rel = append(rel, digit)
+			rel = append(rel, parseNumber(r))
 		}
 		stdVersion.release = rel
 	}
@@ -75,11 +84,7 @@ func ParseVersion(version string) (*Version, error) {
 	if n := match[irregularVersionRe.SubexpIndex("pre_n")]; n != "" {
 		preN = n
 	}
-	pre, err := parseLetterVersion(preL, preN)
-	if err != nil {
-		return nil, fmt.Errorf("not a valid pre version, %s", err.Error())
-	}
-	stdVersion.pre = pre
+	stdVersion.pre = parseLetterVersion(preL, preN)
 
 	var postL, postN string
 	if l := match[irregularVersionRe.SubexpIndex("post_l")]; l != "" {
@@ -90,11 +95,7 @@ func ParseVersion(version string) (*Version, error) {
 	} else if n2 := match[irregularVersionRe.SubexpIndex("post_n2")]; n2 != "" {
 		postN = n2
 	}
-	post, err := parseLetterVersion(postL, postN)
-	if err != nil {
-		return nil, fmt.Errorf("not a valid post version, %s", err.Error())
-	}
-	stdVersion.post = post
+	stdVersion.post = parseLetterVersion(postL, postN)
 
 	var devL, devN string
 	if l := match[irregularVersionRe.SubexpIndex("dev_l")]; l != "" {
@@ -103,11 +104,7 @@ func ParseVersion(version string) (*Version, error) {
 	if n := match[irregularVersionRe.SubexpIndex("dev_n")]; n != "" {
 		devN = n
 	}
-	dev, err := parseLetterVersion(devL, devN)
-	if err != nil {
-		return nil, fmt.Errorf("not a valid dev version, %s", err.Error())
-	}
-	stdVersion.dev = dev
+	stdVersion.dev = parseLetterVersion(devL, devN)
 
 	if local := match[irregularVersionRe.SubexpIndex("local")]; local != "" {
 		stdVersion.local = parseLocalVersion(local)
@@ -116,57 +113,115 @@ func ParseVersion(version string) (*Version, error) {
 	return stdVersion, nil
 }
 
-func parseLetterVersion(letter string, number string) (*Stage, error) {
-	if letter != "" {
-		letter = strings.ToLower(letter)
-		if letter == "alpha" {
-			letter = "a"
-		} else if letter == "beta" {
-			letter = "b"
-		} else if NewSet("c", "pre", "preview").Contains(letter) {
-			letter = "rc"
-		} else if NewSet("rev", "r").Contains(letter) {
-			letter = "post"
+// NewVersion creates a Version from its segments, a nil stage means the segment is absent and an
+// empty local means no local version. The stage names are normalized as ParseVersion does, so the
+// pre-release can be named 'a', 'b' or 'rc' (or 'alpha', 'c', etc.), and the post and dev
+// releases 'post' and 'dev'. Local segments are separated by '.', '-' or '_'.
+func NewVersion(epoch int64, release []int64, pre, post, dev *Stage, local string) (*Version, error) {
+	if epoch < 0 {
+		return nil, &VersionError{Component: ComponentEpoch, Kind: ErrInvalidVersion, Err: fmt.Errorf("negative epoch %d", epoch)}
+	}
+	if len(release) == 0 {
+		return nil, &VersionError{Component: ComponentRelease, Kind: ErrInvalidVersion, Err: fmt.Errorf("empty release")}
+	}
+	for _, r := range release {
+		if r < 0 {
+			return nil, &VersionError{Component: ComponentRelease, Kind: ErrInvalidVersion, Err: fmt.Errorf("negative release %v", release)}
 		}
+	}
 
-		var num int64
-		if number != "" {
-			n, err := strconv.ParseInt(number, 10, 64)
-			if err != nil {
-				return nil, err
-			}
-			num = n
+	v := &Version{
+		epoch: newNumber(epoch),
+	}
+	for _, r := range release {
+		v.release = append(v.release, newNumber(r))
+	}
+
+	var err error
+	if v.pre, err = newStage(pre, "a", "b", "rc"); err != nil {
+		return nil, &VersionError{Component: ComponentPre, Kind: ErrInvalidVersion, Err: err}
+	}
+	if v.post, err = newStage(post, "post"); err != nil {
+		return nil, &VersionError{Component: ComponentPost, Kind: ErrInvalidVersion, Err: err}
+	}
+	if v.dev, err = newStage(dev, "dev"); err != nil {
+		return nil, &VersionError{Component: ComponentDev, Kind: ErrInvalidVersion, Err: err}
+	}
+
+	if local != "" {
+		if !localVersionRe.MatchString(local) {
+			return nil, &VersionError{Component: ComponentLocal, Kind: ErrInvalidVersion, Err: fmt.Errorf("unexpected local '%s'", local)}
 		}
+		v.local = parseLocalVersion(local)
+	}
+
+	return v, nil
+}
 
-		return &Stage{
-			Name:   letter,
-			Number: num,
-		}, nil
+// newStage creates a segment from a stage with its name normalized, names are the allowed ones
+// after normalization.
+func newStage(stage *Stage, names ...string) (*segment, error) {
+	if stage == nil {
+		return nil, nil
 	}
 
-	if number != "" {
-		// this is using the implicit post release syntax (e.g. 1.0-1)
-		letter = "post"
-		num, err := strconv.ParseInt(number, 10, 64)
-		if err != nil {
-			return nil, err
+	name := canonicalLetter(stage.Name)
+	if !NewSet(names...).Contains(name) {
+		return nil, fmt.Errorf("unexpected name '%s'", stage.Name)
+	}
+	if stage.Number < 0 {
+		return nil, fmt.Errorf("unexpected number %d", stage.Number)
+	}
+
+	return &segment{
+		name:   name,
+		number: newNumber(stage.Number),
+	}, nil
+}
+
+func parseLetterVersion(letter string, num string) *segment {
+	if letter != "" {
+		return &segment{
+			name:   canonicalLetter(letter),
+			number: parseNumber(num),
 		}
+	}
 
-		return &Stage{
-			Name:   letter,
-			Number: num,
-		}, nil
+	if num != "" {
+		// this is using the implicit post release syntax (e.g. 1.0-1)
+		return &segment{
+			name:   "post",
+			number: parseNumber(num),
+		}
 	}
 
-	return nil, nil
+	return nil
 }
 
-var localVersionSeparators = regexp.MustCompile(`[-_.]`)
+// canonicalLetter normalizes the alternative spellings of stage names.
+func canonicalLetter(letter string) string {
+	letter = strings.ToLower(letter)
+	switch letter {
+	case "alpha":
+		return "a"
+	case "beta":
+		return "b"
+	case "c", "pre", "preview":
+		return "rc"
+	case "rev", "r":
+		return "post"
+	default:
+		return letter
+	}
+}
 
+// parseLocalVersion splits a valid local version into its segments in lower case.
 func parseLocalVersion(local string) []string {
 	if local != "" {
 		local = strings.ToLower(local)
-		return localVersionSeparators.Split(local, -1)
+		return strings.FieldsFunc(local, func(r rune) bool {
+			return r == '-' || r == '_' || r == '.'
+		})
 	}
 
 	return nil
@@ -181,13 +236,13 @@ func (v *Version) Complete() string {
 	var parts []string
 
 	// epoch
-	if v.epoch != 0 {
-		parts = append(parts, strconv.FormatInt(v.epoch, 10)+"!")
+	if !v.epoch.isZero() {
+		parts = append(parts, string(v.epoch)+"!")
 	}
 	// release
 	var rel []string
 	for _, r := range v.release {
-		rel = append(rel, strconv.FormatInt(r, 10))
+		rel = append(rel, string(r))
 	}
 	parts = append(parts, strings.Join(rel, "."))
 	// pre-release
@@ -220,13 +275,13 @@ func (v *Version) Base() string {
 	var parts []string
 
 	// epoch
-	if v.epoch != 0 {
-		parts = append(parts, strconv.FormatInt(v.epoch, 10)+"!")
+	if !v.epoch.isZero() {
+		parts = append(parts, string(v.epoch)+"!")
 	}
 	// release
 	var rel []string
 	for _, r := range v.release {
-		rel = append(rel, strconv.FormatInt(r, 10))
+		rel = append(rel, string(r))
 	}
 	parts = append(parts, strings.Join(rel, "."))
 
@@ -237,24 +292,264 @@ func (v *Version) Local() string {
 	return strings.Join(v.local, ".")
 }
 
+// LocalSegment is a dot separated segment of local version, see
+// https://peps.python.org/pep-0440/#local-version-identifiers.
+type LocalSegment struct {
+	// Text is the segment in lower case as it's written, e.g. 'cu121' or '01'.
+	Text string
+	// Number is the value of a numeric segment, it's nil for an alphanumeric segment.
+	Number *big.Int
+}
+
+func (s LocalSegment) String() string {
+	return s.Text
+}
+
+func (s LocalSegment) IsNumeric() bool {
+	return s.Number != nil
+}
+
+// Compare returns -1, 0 or +1 if s sorts before, equal to or after other: numeric segments sort
+// after alphanumeric ones and are compared as integers, alphanumeric segments are compared
+// lexicographically, e.g. 'cpu' < 'cu121' < '2' < '10', and '01' equals '1'.
+func (s LocalSegment) Compare(other LocalSegment) int {
+	return compareLocalSegment(s.Text, other.Text)
+}
+
+// LocalSegments returns the segments of local version, or nil if there is none.
+func (v *Version) LocalSegments() []LocalSegment {
+	if len(v.local) == 0 {
+		return nil
+	}
+
+	segments := make([]LocalSegment, len(v.local))
+	for i, l := range v.local {
+		segments[i].Text = l
+		if isDigits(l) {
+			segments[i].Number = parseNumber(l).big()
+		}
+	}
+
+	return segments
+}
+
+// Epoch returns the epoch, or math.MaxInt64 if it overflows int64, see BigEpoch.
 func (v *Version) Epoch() int64 {
-	return v.epoch
+	return v.epoch.int64()
 }
 
+// Release returns a copy of release segment, a Version is never modified after created. Numbers
+// overflowing int64 are math.MaxInt64, see BigRelease.
 func (v *Version) Release() []int64 {
-	return v.release
+	release := make([]int64, len(v.release))
+	for i, r := range v.release {
+		release[i] = r.int64()
+	}
+	return release
 }
 
+// Pre returns a copy of pre-release stage, or nil if there is none.
 func (v *Version) Pre() *Stage {
-	return v.pre
+	return v.pre.stage()
 }
 
+// Post returns a copy of post release stage, or nil if there is none.
 func (v *Version) Post() *Stage {
-	return v.post
+	return v.post.stage()
 }
 
+// Dev returns a copy of dev release stage, or nil if there is none.
 func (v *Version) Dev() *Stage {
-	return v.dev
+	return v.dev.stage()
+}
+
+// BigEpoch returns the exact epoch, which can be larger than math.MaxInt64.
+func (v *Version) BigEpoch() *big.Int {
+	return v.epoch.big()
+}
+
+// BigRelease returns the exact numbers of release segment.
+func (v *Version) BigRelease() []*big.Int {
+	release := make([]*big.Int, len(v.release))
+	for i, r := range v.release {
+		release[i] = r.big()
+	}
+	return release
+}
+
+// BigPre returns the exact number of pre-release stage, or nil if there is none.
+func (v *Version) BigPre() *big.Int {
+	return v.pre.big()
+}
+
+// BigPost returns the exact number of post release stage, or nil if there is none.
+func (v *Version) BigPost() *big.Int {
+	return v.post.big()
+}
+
+// BigDev returns the exact number of dev release stage, or nil if there is none.
+func (v *Version) BigDev() *big.Int {
+	return v.dev.big()
+}
+
+// Compare returns -1, 0 or +1 if v sorts before, equal to or after other. Versions are ordered by
+// the sort key of packaging https://github.com/pypa/packaging/blob/21.3/packaging/version.py#L450,
+// and any version which is not a Version, such as LegacyVersion, sorts before all of them.
+func (v *Version) Compare(other IVersion) int {
+	o, ok := other.(*Version)
+	if !ok {
+		return 1
+	}
+
+	if c := comparePublic(v, o); c != 0 {
+		return c
+	}
+	return compareLocal(v.local, o.local)
+}
+
+func (v *Version) Less(other IVersion) bool {
+	return v.Compare(other) < 0
+}
+
+func (v *Version) Equal(other IVersion) bool {
+	return v.Compare(other) == 0
+}
+
+// comparePublic compares two versions with their local segments ignored.
+func comparePublic(a, b *Version) int {
+	if c := a.epoch.compare(b.epoch); c != 0 {
+		return c
+	}
+	if c := compareRelease(a.release, b.release); c != 0 {
+		return c
+	}
+	if c := comparePre(a, b); c != 0 {
+		return c
+	}
+	// a version without post segment sorts before one with it
+	if c := compareStage(a.post, b.post, -1); c != 0 {
+		return c
+	}
+	// a version without dev segment sorts after one with it
+	return compareStage(a.dev, b.dev, 1)
+}
+
+func compareInt(a, b int64) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
+
+// compareRelease compares release segments with trailing zeros ignored, so 1.0 equals 1.0.0.
+func compareRelease(a, b []number) int {
+	n := len(a)
+	if len(b) > n {
+		n = len(b)
+	}
+
+	for i := 0; i < n; i++ {
+		var x, y number
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if c := x.compare(y); c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+// preRank places a version without pre segment: a dev release of a final release (e.g. 1.0.dev0)
+// sorts before all its pre releases, a final release sorts after them.
+func preRank(v *Version) int {
+	if v.pre != nil {
+		return 0
+	}
+	if v.post == nil && v.dev != nil {
+		return -1
+	}
+	return 1
+}
+
+func comparePre(a, b *Version) int {
+	ra, rb := preRank(a), preRank(b)
+	if ra != rb || ra != 0 {
+		return compareInt(int64(ra), int64(rb))
+	}
+
+	// 'a' < 'b' < 'rc' in lexicographical order
+	if c := strings.Compare(a.pre.name, b.pre.name); c != 0 {
+		return c
+	}
+	return a.pre.number.compare(b.pre.number)
+}
+
+// compareStage compares two stages of the same kind, absent is the result of comparing a missing
+// stage with an existing one.
+func compareStage(a, b *segment, absent int) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return absent
+	case b == nil:
+		return -absent
+	default:
+		return a.number.compare(b.number)
+	}
+}
+
+// compareLocal compares local segments as pep-440 requires: numeric segments sort after alphanumeric
+// ones and are compared as integers, alphanumeric segments are compared lexicographically, and a
+// version with more segments sorts after one with less if all the previous segments are equal.
+func compareLocal(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := compareLocalSegment(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+
+	return compareInt(int64(len(a)), int64(len(b)))
+}
+
+func compareLocalSegment(a, b string) int {
+	na, nb := isDigits(a), isDigits(b)
+	switch {
+	case na && nb:
+		return compareDigits(a, b)
+	case na:
+		return 1
+	case nb:
+		return -1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// compareDigits compares two decimal strings of arbitrary length numerically.
+func compareDigits(a, b string) int {
+	a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if c := compareInt(int64(len(a)), int64(len(b))); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
 }
 
 // LegacyVersion is an irregular version for the compatibility of bdist_dumb format, an old-aged
@@ -262,6 +557,7 @@ func (v *Version) Dev() *Stage {
 // version has been deprecated in packaging, but now still available in pip.
 type LegacyVersion struct {
 	version string
+	parts   []string
 }
 
 // ParseLegacyVersion implements a legacy version parser with reference to packaging, an official
@@ -269,9 +565,74 @@ type LegacyVersion struct {
 func ParseLegacyVersion(version string) (*LegacyVersion, error) {
 	return &LegacyVersion{
 		version: version,
+		parts:   parseLegacyVersionParts(version),
 	}, nil
 }
 
+var legacyVersionComponentRe = regexp.MustCompile(`\d+|[a-z]+|\.|-`)
+
+var legacyVersionReplacements = map[string]string{
+	"pre":     "c",
+	"preview": "c",
+	"-":       "final-",
+	"rc":      "c",
+	"dev":     "@",
+}
+
+// parseLegacyVersionParts builds the comparison key of a legacy version, which is the same as the
+// _legacy_cmpkey of packaging https://github.com/pypa/packaging/blob/21.3/packaging/version.py#L168.
+// Numeric parts are padded to 8 digits, other parts are prefixed with '*' and a '*final' part is
+// appended to make sure that alpha, beta and candidate parts sort before final releases.
+func parseLegacyVersionParts(version string) []string {
+	version = strings.ToLower(version)
+
+	var components []string
+	last := 0
+	for _, loc := range legacyVersionComponentRe.FindAllStringIndex(version, -1) {
+		components = append(components, version[last:loc[0]], version[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	components = append(components, version[last:])
+
+	var parts []string
+	push := func(part string) {
+		if strings.HasPrefix(part, "*") {
+			// remove "-" before a pre-release tag
+			if part < "*final" {
+				for len(parts) > 0 && parts[len(parts)-1] == "*final-" {
+					parts = parts[:len(parts)-1]
+				}
+			}
+			// remove trailing zeros from each series of numeric parts
+			for len(parts) > 0 && parts[len(parts)-1] == "00000000" {
+				parts = parts[:len(parts)-1]
+			}
+		}
+		parts = append(parts, part)
+	}
+
+	for _, component := range components {
+		if r, ok := legacyVersionReplacements[component]; ok {
+			component = r
+		}
+		if component == "" || component == "." {
+			continue
+		}
+		if component[0] >= '0' && component[0] <= '9' {
+			// pad for numeric comparison
+			if len(component) < 8 {
+				component = strings.Repeat("0", 8-len(component)) + component
+			}
+			push(component)
+		} else {
+			push("*" + component)
+		}
+	}
+	push("*final")
+
+	return parts
+}
+
 func (v *LegacyVersion) String() string {
 	return fmt.Sprintf("LegacyVersion<%s>", v.version)
 }
@@ -292,6 +653,7 @@ func (v *LegacyVersion) Local() string {
 	return ""
 }
 
+// Epoch returns -1 for a legacy version, which makes it sort before every Version.
 func (v *LegacyVersion) Epoch() int64 {
 	return -1
 }
@@ -312,6 +674,31 @@ func (v *LegacyVersion) Dev() *Stage {
 	return nil
 }
 
+// Compare returns -1, 0 or +1 if v sorts before, equal to or after other. LegacyVersions are
+// compared by their parts as packaging does, and always sort before a Version because of the
+// implicit epoch of -1.
+func (v *LegacyVersion) Compare(other IVersion) int {
+	o, ok := other.(*LegacyVersion)
+	if !ok {
+		return -1
+	}
+
+	for i := 0; i < len(v.parts) && i < len(o.parts); i++ {
+		if c := strings.Compare(v.parts[i], o.parts[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInt(int64(len(v.parts)), int64(len(o.parts)))
+}
+
+func (v *LegacyVersion) Less(other IVersion) bool {
+	return v.Compare(other) < 0
+}
+
+func (v *LegacyVersion) Equal(other IVersion) bool {
+	return v.Compare(other) == 0
+}
+
 // Parse canonicalizes a Version from original version string, fallback to LegacyVersion if version
 // string is irregular, https://github.com/pypa/packaging/blob/21.3/packaging/version.py#L42.
 func Parse(version string) (IVersion, error) {
@@ -2,6 +2,7 @@ package version
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 	"testing"
 )
@@ -667,3 +668,395 @@ func TestVersionDev(t *testing.T) {
 		})
 	}
 }
+
+func TestVersionCompare(t *testing.T) {
+	// sorted in ascending order
+	var sortedVersions = []string{
+		// Implicit epoch of 0
+		"1.0.dev456",
+		"1.0a1",
+		"1.0a2.dev456",
+		"1.0a12.dev456",
+		"1.0a12",
+		"1.0b1.dev456",
+		"1.0b2",
+		"1.0b2.post345.dev456",
+		"1.0b2.post345",
+		"1.0b2-346",
+		"1.0c1.dev456",
+		"1.0c1",
+		"1.0rc2",
+		"1.0c3",
+		"1.0",
+		"1.0.post456.dev34",
+		"1.0.post456",
+		"1.1.dev1",
+		"1.2+123abc",
+		"1.2+123abc456",
+		"1.2+abc",
+		"1.2+abc123",
+		"1.2+abc123def",
+		"1.2+1234.abc",
+		"1.2+123456",
+		"1.2.r32+123456",
+		"1.2.rev33+123456",
+		// Explicit epoch of 1
+		"1!1.0.dev456",
+		"1!1.0a1",
+		"1!1.0a2.dev456",
+		"1!1.0a12.dev456",
+		"1!1.0a12",
+		"1!1.0b1.dev456",
+		"1!1.0b2",
+		"1!1.0b2.post345.dev456",
+		"1!1.0b2.post345",
+		"1!1.0b2-346",
+		"1!1.0c1.dev456",
+		"1!1.0c1",
+		"1!1.0rc2",
+		"1!1.0c3",
+		"1!1.0",
+		"1!1.0.post456.dev34",
+		"1!1.0.post456",
+		"1!1.1.dev1",
+		"1!1.2+123abc",
+		"1!1.2+123abc456",
+		"1!1.2+abc",
+		"1!1.2+abc123",
+		"1!1.2+abc123def",
+		"1!1.2+1234.abc",
+		"1!1.2+123456",
+		"1!1.2.r32+123456",
+		"1!1.2.rev33+123456",
+	}
+
+	var versions []IVersion
+	for _, version := range sortedVersions {
+		v, err := ParseVersion(version)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		versions = append(versions, v)
+	}
+
+	for i, a := range versions {
+		for j, b := range versions {
+			var expected int
+			if i < j {
+				expected = -1
+			} else if i > j {
+				expected = 1
+			}
+			if c := a.Compare(b); c != expected {
+				t.Errorf("compare %s with %s, %d(actual) != %d(expected)", a.Complete(), b.Complete(), c, expected)
+			}
+			if a.Less(b) != (expected < 0) || a.Equal(b) != (expected == 0) {
+				t.Errorf("inconsistent Less/Equal between %s and %s", a.Complete(), b.Complete())
+			}
+		}
+	}
+}
+
+func TestVersionEqual(t *testing.T) {
+	var versionEquals = []struct {
+		left  string
+		right string
+		equal bool
+	}{
+		{"1.0", "1.0.0", true},
+		{"1.0", "1.0.0.0", true},
+		{"1.0a1", "1.0.0a1", true},
+		{"1.0.post1", "1.0.0.post1", true},
+		{"1.0+1", "1.0+01", true},
+		{"1.0+abc", "1.0+ABC", true},
+		{"0!1.0", "1.0", true},
+		{"1.0", "1.0+0", false},
+		{"1.0", "1!1.0", false},
+		{"1.0.0.1", "1.0", false},
+		{"1.0.dev0", "1.0a0", false},
+	}
+
+	for _, equal := range versionEquals {
+		t.Run(equal.left+"=="+equal.right, func(t *testing.T) {
+			left, err := ParseVersion(equal.left)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			right, err := ParseVersion(equal.right)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if left.Equal(right) != equal.equal || right.Equal(left) != equal.equal {
+				t.Errorf("%s == %s, %t(expected)", equal.left, equal.right, equal.equal)
+			}
+		})
+	}
+}
+
+func TestLegacyVersionCompare(t *testing.T) {
+	// sorted in ascending order, the last ones are regular versions
+	var sortedVersions = []string{
+		"french toast",
+		"french toast2",
+		"1.0-beta-x",
+		"1.0-x",
+		"1.0.macosx-10.5",
+		"1.0.macosx-10.11",
+		"1.1.macosx-10.5",
+		"0.0.dev0",
+		"1.0",
+	}
+
+	var versions []IVersion
+	for _, version := range sortedVersions {
+		v, err := Parse(version)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		versions = append(versions, v)
+	}
+
+	for i, a := range versions {
+		for j, b := range versions {
+			var expected int
+			if i < j {
+				expected = -1
+			} else if i > j {
+				expected = 1
+			}
+			if c := a.Compare(b); c != expected {
+				t.Errorf("compare %s with %s, %d(actual) != %d(expected)", a, b, c, expected)
+			}
+		}
+	}
+
+	left, _ := ParseLegacyVersion("1.0.macosx-10.5")
+	right, _ := ParseLegacyVersion("1.0.0.MACOSX-10.5")
+	if !left.Equal(right) {
+		t.Errorf("%s != %s", left, right)
+	}
+}
+
+func TestNewVersion(t *testing.T) {
+	var newVersionCases = []struct {
+		epoch    int64
+		release  []int64
+		pre      *Stage
+		post     *Stage
+		dev      *Stage
+		local    string
+		expected string
+		failed   bool
+	}{
+		{0, []int64{1, 0}, nil, nil, nil, "", "1.0", false},
+		{1, []int64{2}, &Stage{"rc", 1}, &Stage{"post", 2}, &Stage{"dev", 3}, "ubuntu-1", "1!2rc1.post2.dev3+ubuntu.1", false},
+		{0, []int64{1, 0}, &Stage{"alpha", 1}, &Stage{"rev", 0}, nil, "", "1.0a1.post0", false},
+		{0, []int64{1, 0}, &Stage{"PREVIEW", 1}, nil, nil, "ABC", "1.0rc1+abc", false},
+		{-1, []int64{1, 0}, nil, nil, nil, "", "", true},
+		{0, nil, nil, nil, nil, "", "", true},
+		{0, []int64{1, -1}, nil, nil, nil, "", "", true},
+		{0, []int64{1}, &Stage{"post", 1}, nil, nil, "", "", true},
+		{0, []int64{1}, nil, &Stage{"dev", 1}, nil, "", "", true},
+		{0, []int64{1}, nil, nil, &Stage{"dev", -1}, "", "", true},
+		{0, []int64{1}, nil, nil, nil, "abc+", "", true},
+	}
+
+	for _, c := range newVersionCases {
+		t.Run(c.expected, func(t *testing.T) {
+			v, err := NewVersion(c.epoch, c.release, c.pre, c.post, c.dev, c.local)
+			if c.failed != (err != nil) {
+				t.Error(err)
+				return
+			}
+			if err == nil && v.Complete() != c.expected {
+				t.Errorf("%s(actual) != %s(expected)", v.Complete(), c.expected)
+			}
+		})
+	}
+}
+
+func TestVersionImmutable(t *testing.T) {
+	v, err := ParseVersion("1.2rc1")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	v.Release()[0] = 3
+	v.Pre().Number = 3
+	if v.Complete() != "1.2rc1" {
+		t.Errorf("%s(actual) != 1.2rc1(expected)", v.Complete())
+	}
+}
+
+func TestVersionBump(t *testing.T) {
+	var bumpCases = []struct {
+		version  string
+		bump     func(v *Version) (*Version, error)
+		expected string
+	}{
+		{"1.2.3", func(v *Version) (*Version, error) { return v.BumpRelease(0) }, "2.0.0"},
+		{"1.2.3", func(v *Version) (*Version, error) { return v.BumpRelease(1) }, "1.3.0"},
+		{"1.2.3", func(v *Version) (*Version, error) { return v.BumpRelease(2) }, "1.2.4"},
+		{"1.2", func(v *Version) (*Version, error) { return v.BumpRelease(3) }, "1.2.0.1"},
+		{"1.99999999999999999999", func(v *Version) (*Version, error) { return v.BumpRelease(1) }, "1.100000000000000000000"},
+		{"1!2.0rc1.post1.dev1+local", func(v *Version) (*Version, error) { return v.BumpRelease(0) }, "1!3.0"},
+		{"1.0", func(v *Version) (*Version, error) { return v.BumpRelease(-1) }, ""},
+		{"1.0rc1", func(v *Version) (*Version, error) { return v.NextPre("rc") }, "1.0rc2"},
+		{"1.0rc1.post1.dev1", func(v *Version) (*Version, error) { return v.NextPre("c") }, "1.0rc2"},
+		{"1.0b2", func(v *Version) (*Version, error) { return v.NextPre("rc") }, "1.0rc0"},
+		{"1.0", func(v *Version) (*Version, error) { return v.NextPre("alpha") }, "1.0a0"},
+		{"1.0", func(v *Version) (*Version, error) { return v.NextPre("post") }, ""},
+		{"1.0rc1", func(v *Version) (*Version, error) { return v.NextPre("a") }, ""},
+		{"1.0b1.dev0", func(v *Version) (*Version, error) { return v.NextPre("alpha") }, ""},
+		{"1.0b1.post2", func(v *Version) (*Version, error) { return v.NextPre("b") }, "1.0b2"},
+		{"1.0b1.dev0", func(v *Version) (*Version, error) { return v.NextPre("b") }, "1.0b1"},
+		{"1.0b1.dev0", func(v *Version) (*Version, error) { return v.NextPre("rc") }, "1.0rc0"},
+		{"1.0", func(v *Version) (*Version, error) { return v.NextPost(), nil }, "1.0.post0"},
+		{"1.0.post1.dev2+local", func(v *Version) (*Version, error) { return v.NextPost(), nil }, "1.0.post1"},
+		{"1.0.post1+local", func(v *Version) (*Version, error) { return v.NextPost(), nil }, "1.0.post2"},
+		{"1.0a1", func(v *Version) (*Version, error) { return v.NextDev(), nil }, "1.0a1.dev0"},
+		{"1.0.dev1+local", func(v *Version) (*Version, error) { return v.NextDev(), nil }, "1.0.dev2"},
+		{"1.0+local", func(v *Version) (*Version, error) { return v.DropLocal(), nil }, "1.0"},
+		{"1.0+local", func(v *Version) (*Version, error) { return v.WithLocal("cu121") }, "1.0+cu121"},
+		{"1.0", func(v *Version) (*Version, error) { return v.WithLocal("Ubuntu-20_04") }, "1.0+ubuntu.20.04"},
+		{"1.0+local", func(v *Version) (*Version, error) { return v.WithLocal("") }, "1.0"},
+		{"1.0", func(v *Version) (*Version, error) { return v.WithLocal("a..b") }, ""},
+	}
+
+	for _, c := range bumpCases {
+		t.Run(c.version+"->"+c.expected, func(t *testing.T) {
+			v, err := ParseVersion(c.version)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			original := v.Complete()
+
+			bumped, err := c.bump(v)
+			if (c.expected == "") != (err != nil) {
+				t.Error(err)
+				return
+			}
+			if err == nil && bumped.Complete() != c.expected {
+				t.Errorf("%s(actual) != %s(expected)", bumped.Complete(), c.expected)
+			}
+			if v.Complete() != original {
+				t.Errorf("%s is modified to %s", original, v.Complete())
+			}
+		})
+	}
+}
+
+func TestVersionBigNumbers(t *testing.T) {
+	var bigCases = []struct {
+		version  string
+		expected string
+	}{
+		{"20230101123045123456789", "20230101123045123456789"},
+		{"099999999999999999999!1.0", "99999999999999999999!1.0"},
+		{"1.0a0099999999999999999999", "1.0a99999999999999999999"},
+		{"1.0-99999999999999999999", "1.0.post99999999999999999999"},
+		{"1.0.dev99999999999999999999+1", "1.0.dev99999999999999999999+1"},
+	}
+
+	for _, c := range bigCases {
+		t.Run(c.version, func(t *testing.T) {
+			v, err := ParseVersion(c.version)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if v.Complete() != c.expected {
+				t.Errorf("%s(actual) != %s(expected)", v.Complete(), c.expected)
+			}
+		})
+	}
+
+	var orderedVersions = []string{
+		"9223372036854775807",
+		"9223372036854775808",
+		"20230101123045123456789",
+		"20230101123045123456789.1",
+		"20230101123045123456790.dev0",
+		"20230101123045123456790a9223372036854775808",
+		"20230101123045123456790",
+		"20230101123045123456790.post99999999999999999999",
+		"1!0",
+		"99999999999999999999!0",
+	}
+	for i := 0; i+1 < len(orderedVersions); i++ {
+		a, _ := ParseVersion(orderedVersions[i])
+		b, _ := ParseVersion(orderedVersions[i+1])
+		if !a.Less(b) || !b.Equal(b) {
+			t.Errorf("%s should be less than %s", a, b)
+		}
+	}
+
+	v, _ := ParseVersion("99999999999999999999!1.99999999999999999999rc99999999999999999999")
+	if v.Epoch() != math.MaxInt64 || v.Release()[1] != math.MaxInt64 || v.Pre().Number != math.MaxInt64 {
+		t.Errorf("%v, %v, %v should be saturated", v.Epoch(), v.Release(), v.Pre())
+	}
+	if v.BigEpoch().String() != "99999999999999999999" || v.BigRelease()[1].String() != "99999999999999999999" || v.BigPre().String() != "99999999999999999999" {
+		t.Errorf("%v, %v, %v should be exact", v.BigEpoch(), v.BigRelease(), v.BigPre())
+	}
+	if v.BigPost() != nil || v.BigDev() != nil {
+		t.Errorf("%v, %v should be nil", v.BigPost(), v.BigDev())
+	}
+}
+
+func TestVersionLocalSegments(t *testing.T) {
+	v, err := ParseVersion("2.1.0+Ubuntu-20_04.cu121.0099999999999999999999")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	segments := v.LocalSegments()
+	var texts []string
+	for _, s := range segments {
+		texts = append(texts, s.String())
+	}
+	if expected := []string{"ubuntu", "20", "04", "cu121", "0099999999999999999999"}; !reflect.DeepEqual(texts, expected) {
+		t.Errorf("%v(actual) != %v(expected)", texts, expected)
+	}
+	if segments[0].IsNumeric() || !segments[2].IsNumeric() || segments[2].Number.Int64() != 4 {
+		t.Errorf("%v should be alphanumeric and %v should be 4", segments[0], segments[2])
+	}
+	if segments[4].Number.String() != "99999999999999999999" {
+		t.Errorf("%v(actual) != 99999999999999999999(expected)", segments[4].Number)
+	}
+	if segments[2].Compare(LocalSegment{Text: "4"}) != 0 || segments[3].Compare(segments[1]) >= 0 {
+		t.Errorf("%v should be equal to 4, and %v should be less than %v", segments[2], segments[3], segments[1])
+	}
+
+	if v, _ := ParseVersion("1.0"); v.LocalSegments() != nil {
+		t.Errorf("%v should have no local segments", v)
+	}
+
+	// torch style builds are ordered by their local versions
+	var orderedVersions = []string{
+		"2.1.0",
+		"2.1.0+cpu",
+		"2.1.0+cpu.cxx11.abi",
+		"2.1.0+cu118",
+		"2.1.0+cu121",
+		"2.1.0+rocm5.6",
+		"2.1.0+1",
+		"2.1.0+2",
+		"2.1.0+10",
+		"2.1.0+10.a",
+		"2.1.0+10.1",
+	}
+	for i := 0; i+1 < len(orderedVersions); i++ {
+		a, _ := ParseVersion(orderedVersions[i])
+		b, _ := ParseVersion(orderedVersions[i+1])
+		if !a.Less(b) {
+			t.Errorf("%s should be less than %s", a, b)
+		}
+	}
+}
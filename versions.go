@@ -0,0 +1,156 @@
+package version
+
+import (
+	"sort"
+	"strings"
+)
+
+// Versions is a collection of versions, it implements sort.Interface in ascending order.
+type Versions []IVersion
+
+// ParseVersions parses every version string with Parse, irregular ones become LegacyVersions.
+func ParseVersions(versions []string) (Versions, error) {
+	vs := make(Versions, 0, len(versions))
+	for _, version := range versions {
+		v, err := Parse(version)
+		if err != nil {
+			return nil, err
+		}
+		vs = append(vs, v)
+	}
+
+	return vs, nil
+}
+
+func (vs Versions) Len() int {
+	return len(vs)
+}
+
+func (vs Versions) Less(i, j int) bool {
+	return vs[i].Less(vs[j])
+}
+
+func (vs Versions) Swap(i, j int) {
+	vs[i], vs[j] = vs[j], vs[i]
+}
+
+// Sort sorts versions in ascending order, equal versions (e.g. 1.0 and 1.0.0) keep their order.
+func (vs Versions) Sort() {
+	sort.Stable(vs)
+}
+
+// Max returns the greatest version, or nil if there is none. The first one wins among equal versions.
+func (vs Versions) Max() IVersion {
+	var max IVersion
+	for _, v := range vs {
+		if max == nil || v.Compare(max) > 0 {
+			max = v
+		}
+	}
+
+	return max
+}
+
+// Min returns the least version, or nil if there is none. The first one wins among equal versions.
+func (vs Versions) Min() IVersion {
+	var min IVersion
+	for _, v := range vs {
+		if min == nil || v.Compare(min) < 0 {
+			min = v
+		}
+	}
+
+	return min
+}
+
+// LatestStable returns the greatest version which is neither a pre-release nor a dev release, or
+// nil if there is none. Post releases and local versions are considered as stable.
+func (vs Versions) LatestStable() IVersion {
+	var latest IVersion
+	for _, v := range vs {
+		if isPrerelease(v) {
+			continue
+		}
+		if latest == nil || v.Compare(latest) > 0 {
+			latest = v
+		}
+	}
+
+	return latest
+}
+
+// GroupBy groups versions by the series made up of the first depth release segments, the missing
+// segments are regarded as zeros and a non-zero epoch is kept, e.g. 2.1.3 and 2 are in series "2"
+// and "2.0" respectively with depth 1 and 2. LegacyVersions have no release and are grouped in "".
+// Versions keep their order in each group.
+func (vs Versions) GroupBy(depth int) map[string]Versions {
+	if depth < 1 {
+		depth = 1
+	}
+
+	groups := make(map[string]Versions)
+	for _, v := range vs {
+		series := versionSeries(v, depth)
+		groups[series] = append(groups[series], v)
+	}
+
+	return groups
+}
+
+func versionSeries(iv IVersion, depth int) string {
+	v, ok := iv.(*Version)
+	if !ok {
+		return ""
+	}
+
+	var parts []string
+	for i := 0; i < depth; i++ {
+		r := "0"
+		if i < len(v.release) {
+			r = string(v.release[i])
+		}
+		parts = append(parts, r)
+	}
+
+	series := strings.Join(parts, ".")
+	if !v.epoch.isZero() {
+		series = string(v.epoch) + "!" + series
+	}
+
+	return series
+}
+
+// Dedup removes versions which are equal to a previous one, such as 1.0.0 after 1.0, the rest
+// keep their order.
+func (vs Versions) Dedup() Versions {
+	sorted := make([]int, len(vs))
+	for i := range sorted {
+		sorted[i] = i
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return vs[sorted[i]].Less(vs[sorted[j]])
+	})
+
+	duplicated := make([]bool, len(vs))
+	for i := 1; i < len(sorted); i++ {
+		if vs[sorted[i]].Equal(vs[sorted[i-1]]) {
+			// the stable sort puts the first one of equal versions ahead
+			duplicated[sorted[i]] = true
+		}
+	}
+
+	deduped := make(Versions, 0, len(vs))
+	for i, v := range vs {
+		if !duplicated[i] {
+			deduped = append(deduped, v)
+		}
+	}
+
+	return deduped
+}
+
+// isPrerelease reports whether a version is a pre-release or a dev release, which is the same
+// as is_prerelease of packaging.
+func isPrerelease(v IVersion) bool {
+	return v.Pre() != nil || v.Dev() != nil
+}
@@ -0,0 +1,85 @@
+package version
+
+import (
+	"reflect"
+	"testing"
+)
+
+func completes(vs Versions) []string {
+	var s []string
+	for _, v := range vs {
+		s = append(s, v.Complete())
+	}
+	return s
+}
+
+func TestVersionsSort(t *testing.T) {
+	vs, err := ParseVersions([]string{"1.0", "1.0-x", "2.0rc1", "1.0.0", "0.9.post1", "1.0.dev1", "1!0.1", "1.0+local"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	vs.Sort()
+	expected := []string{"1.0-x", "0.9.post1", "1.0.dev1", "1.0", "1.0.0", "1.0+local", "2.0rc1", "1!0.1"}
+	if actual := completes(vs); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("%v(actual) != %v(expected)", actual, expected)
+	}
+}
+
+func TestVersionsQuery(t *testing.T) {
+	vs, err := ParseVersions([]string{"1.9", "2.0", "2.1.dev3", "2.0.1", "3.0a1", "2.0.0", "2.1rc1", "0.1-beta-x"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if max := vs.Max(); max.Complete() != "3.0a1" {
+		t.Errorf("max %s(actual) != 3.0a1(expected)", max.Complete())
+	}
+	if min := vs.Min(); min.Complete() != "0.1-beta-x" {
+		t.Errorf("min %s(actual) != 0.1-beta-x(expected)", min.Complete())
+	}
+	if latest := vs.LatestStable(); latest.Complete() != "2.0.1" {
+		t.Errorf("latest stable %s(actual) != 2.0.1(expected)", latest.Complete())
+	}
+	if latest := vs.GroupBy(1)["2"].LatestStable(); latest.Complete() != "2.0.1" {
+		t.Errorf("latest stable of 2.x %s(actual) != 2.0.1(expected)", latest.Complete())
+	}
+	if latest := vs.GroupBy(2)["2.1"].Max(); latest.Complete() != "2.1rc1" {
+		t.Errorf("latest of 2.1.x %s(actual) != 2.1rc1(expected)", latest.Complete())
+	}
+	if empty := (Versions{}); empty.Max() != nil || empty.Min() != nil || empty.LatestStable() != nil {
+		t.Error("empty versions should have no max, min or latest stable")
+	}
+
+	groups := vs.GroupBy(2)
+	expected := map[string][]string{
+		"1.9": {"1.9"},
+		"2.0": {"2.0", "2.0.1", "2.0.0"},
+		"2.1": {"2.1.dev3", "2.1rc1"},
+		"3.0": {"3.0a1"},
+		"":    {"0.1-beta-x"},
+	}
+	if len(groups) != len(expected) {
+		t.Errorf("%d groups(actual) != %d groups(expected)", len(groups), len(expected))
+	}
+	for series, versions := range expected {
+		if actual := completes(groups[series]); !reflect.DeepEqual(actual, versions) {
+			t.Errorf("series %s, %v(actual) != %v(expected)", series, actual, versions)
+		}
+	}
+}
+
+func TestVersionsDedup(t *testing.T) {
+	vs, err := ParseVersions([]string{"1.0.0", "2.0", "1.0", "1!1.0", "1.0+local", "2.0.0.0", "1.0.0", "foo", "FOO"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := []string{"1.0.0", "2.0", "1!1.0", "1.0+local", "foo"}
+	if actual := completes(vs.Dedup()); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("%v(actual) != %v(expected)", actual, expected)
+	}
+}